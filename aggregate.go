@@ -0,0 +1,92 @@
+package tree
+
+import (
+	tea "charm.land/bubbletea/v2"
+)
+
+// Aggregate computes values over the subtrees of a tree, like the total size of the files
+// in a directory. The value of a node without children is computed by Leaf, and the value
+// of any other node is computed by Reduce from the values of its children.
+//
+// The values are cached, and recomputed only for the nodes whose subtree changed, which
+// are reported by Invalidate, or by the ExpandedMsg and ChangedMsg messages passed to Update.
+// The nodes are used as map keys, so their dynamic types must be comparable.
+type Aggregate[V any] struct {
+	Leaf   func(n Node) V
+	Reduce func(n Node, children []V) V
+
+	values map[Node]V
+}
+
+// NewAggregate returns an Aggregate using the leaf and reduce functions.
+func NewAggregate[V any](leaf func(Node) V, reduce func(Node, []V) V) *Aggregate[V] {
+	return &Aggregate[V]{Leaf: leaf, Reduce: reduce, values: make(map[Node]V)}
+}
+
+// Value returns the value for node n, computing it for the parts of its subtree
+// which are not cached.
+func (a *Aggregate[V]) Value(n Node) V {
+	if v, ok := a.values[n]; ok {
+		return v
+	}
+	if a.values == nil {
+		a.values = make(map[Node]V)
+	}
+
+	var v V
+	if children := n.Children(); len(children) > 0 {
+		values := make([]V, 0, len(children))
+		for _, c := range children {
+			if c == nil || isHidden(c) {
+				continue
+			}
+			values = append(values, a.Value(c))
+		}
+		v = a.Reduce(n, values)
+	} else {
+		v = a.Leaf(n)
+	}
+	a.values[n] = v
+	return v
+}
+
+// Invalidate discards the cached values of node n and of its ancestors,
+// which get recomputed the next time they are needed.
+func (a *Aggregate[V]) Invalidate(n Node) {
+	for ; n != nil; n = n.Parent() {
+		delete(a.values, n)
+	}
+}
+
+// Reset discards all the cached values.
+func (a *Aggregate[V]) Reset() {
+	clear(a.values)
+}
+
+// Update invalidates the values of the nodes whose children were loaded, or changed,
+// according to the ExpandedMsg and ChangedMsg messages.
+func (a *Aggregate[V]) Update(msg tea.Msg) {
+	switch m := msg.(type) {
+	case ExpandedMsg:
+		a.Invalidate(m.Node)
+	case ChangedMsg:
+		for _, n := range m.Added {
+			a.Invalidate(n)
+		}
+		for _, n := range m.Modified {
+			a.Invalidate(n)
+		}
+		for _, n := range m.Removed {
+			a.forget(n)
+			a.Invalidate(n)
+		}
+	}
+}
+
+// forget discards the cached values of the subtree of node n.
+func (a *Aggregate[V]) forget(n Node) {
+	delete(a.values, n)
+	for _, c := range n.Children() {
+		a.forget(c)
+	}
+}
@@ -0,0 +1,91 @@
+package tree
+
+import (
+	"testing"
+)
+
+type count struct {
+	leaves, nodes int
+}
+
+func countAggregate(calls *int) *Aggregate[count] {
+	return NewAggregate(
+		func(Node) count {
+			*calls++
+			return count{leaves: 1, nodes: 1}
+		},
+		func(_ Node, children []count) count {
+			*calls++
+			c := count{nodes: 1}
+			for _, v := range children {
+				c.leaves += v.leaves
+				c.nodes += v.nodes
+			}
+			return c
+		},
+	)
+}
+
+func TestAggregate_Value(t *testing.T) {
+	tree := newTreeOne()
+	tests := []struct {
+		name string
+		node Node
+		want count
+	}{
+		{name: "leaf", node: tree.c[0], want: count{leaves: 1, nodes: 1}},
+		{name: "lastchild", node: tree.c[1].c[0].c[2], want: count{leaves: 1, nodes: 2}},
+		{name: "test", node: tree.c[1], want: count{leaves: 6, nodes: 9}},
+		{name: "root", node: tree, want: count{leaves: 7, nodes: 11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			a := countAggregate(&calls)
+			if got := a.Value(tt.node); got != tt.want {
+				t.Errorf("Value() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregate_incremental(t *testing.T) {
+	tree := newTreeOne()
+	calls := 0
+	a := countAggregate(&calls)
+	a.Value(tree)
+	if calls != 11 {
+		t.Fatalf("first Value() computed %d nodes, want 11", calls)
+	}
+
+	calls = 0
+	a.Value(tree)
+	if calls != 0 {
+		t.Errorf("cached Value() computed %d nodes, want 0", calls)
+	}
+
+	// add a child to example, which invalidates example, test and tmp
+	example := tree.c[1].c[0]
+	added := tn("added", p(example))
+	example.c = append(example.c, added)
+	a.Update(ChangedMsg{Added: Nodes{added}})
+
+	calls = 0
+	if got, want := a.Value(tree), (count{leaves: 8, nodes: 12}); got != want {
+		t.Errorf("Value() after adding a node = %+v, want %+v", got, want)
+	}
+	if calls != 4 {
+		t.Errorf("Value() after adding a node computed %d nodes, want 4", calls)
+	}
+
+	// remove test, which invalidates tmp
+	test := tree.c[1]
+	tree.c = tree.c[:1]
+	a.Update(ChangedMsg{Removed: Nodes{test}})
+	if got, want := a.Value(tree), (count{leaves: 1, nodes: 2}); got != want {
+		t.Errorf("Value() after removing a node = %+v, want %+v", got, want)
+	}
+	if _, ok := a.values[test.c[0]]; ok {
+		t.Errorf("the values of the removed subtree are still cached")
+	}
+}
@@ -0,0 +1,417 @@
+// Package archivetree provides tree nodes for browsing the contents of zip and tar archives,
+// without extracting them.
+//
+// The tar archives can be compressed with gzip. The directories are shown even when the
+// archive only contains entries for the files inside them, and the archives found inside
+// an archive are opened the first time they are expanded:
+//
+//	root, err := archivetree.Open("release.tar.gz")
+//	if err != nil {
+//		return err
+//	}
+//	defer root.Close()
+//	t := tree.New(tree.Nodes{root})
+//	t.Columns = archivetree.Columns()
+package archivetree
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	tree "github.com/mariusor/bubbles-tree"
+	"github.com/mariusor/bubbles-tree/internal/size"
+)
+
+// ErrFormat is returned for the files which are not zip or tar archives.
+var ErrFormat = errors.New("not a zip or tar archive")
+
+// Node is a tree.Node for an archive, or one of its files or directories.
+type Node struct {
+	parent *Node
+	name   string
+	// path is the name of the entry in its archive.
+	path    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+	link    string
+	// implicit is set for the directories which don't have an entry in the archive.
+	implicit bool
+
+	// open reads the contents of the entry, and is only set for the nested archives.
+	open   func() ([]byte, error)
+	closer io.Closer
+	loaded bool
+	err    error
+
+	children []*Node
+	state    tree.NodeState
+}
+
+// Open opens the archive file at name. The file is kept open for reading the nested archives,
+// until the node is closed.
+func Open(name string) (*Node, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	n, err := New(filepath.Base(name), f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	n.mode, n.modTime = info.Mode(), info.ModTime()
+	n.closer = f
+	return n, nil
+}
+
+// New builds the nodes for the archive read from r, whose size is size.
+func New(name string, r io.ReaderAt, size int64) (*Node, error) {
+	n := &Node{name: name, size: size, state: tree.NodeCollapsible}
+	if err := n.list(r, size); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Close closes the archive file opened by Open.
+func (n *Node) Close() error {
+	if n.closer == nil {
+		return nil
+	}
+	return n.closer.Close()
+}
+
+// list reads the entries of the archive r, adding them as the children of the node.
+func (n *Node) list(r io.ReaderAt, size int64) error {
+	n.loaded = true
+	header := make([]byte, 512)
+	l, _ := r.ReadAt(header, 0)
+	header = header[:l]
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")), bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		return n.listZip(r, size)
+	case bytes.HasPrefix(header, []byte("\x1f\x8b")):
+		return n.listTar(r, size, true)
+	case len(header) >= 262 && bytes.HasPrefix(header[257:], []byte("ustar")):
+		return n.listTar(r, size, false)
+	}
+	return ErrFormat
+}
+
+func (n *Node) listZip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		info := f.FileInfo()
+		c := n.add(f.Name, info.Mode(), info.Size(), f.Modified)
+		if c != nil && !c.IsDir() && isArchive(c.name) {
+			c.open = func() ([]byte, error) {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(rc)
+			}
+		}
+	}
+	n.sort()
+	return nil
+}
+
+func (n *Node) listTar(r io.ReaderAt, size int64, compressed bool) error {
+	tr, closer, err := openTar(r, size, compressed)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+	for i := 0; ; i++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			n.sort()
+			return err
+		}
+		c := n.add(hdr.Name, hdr.FileInfo().Mode(), hdr.Size, hdr.ModTime)
+		if c == nil {
+			continue
+		}
+		if hdr.Typeflag == tar.TypeSymlink || hdr.Typeflag == tar.TypeLink {
+			c.link = hdr.Linkname
+		}
+		if isArchive(c.name) && hdr.Typeflag == tar.TypeReg {
+			// The tar archives can't be read at random, so the archive is read again
+			// up to the entry when it is opened.
+			index := i
+			c.open = func() ([]byte, error) {
+				return readTarEntry(r, size, compressed, index)
+			}
+		}
+	}
+	n.sort()
+	return nil
+}
+
+func openTar(r io.ReaderAt, size int64, compressed bool) (*tar.Reader, io.Closer, error) {
+	var rd io.Reader = io.NewSectionReader(r, 0, size)
+	closer := io.NopCloser(nil)
+	if compressed {
+		zr, err := gzip.NewReader(rd)
+		if err != nil {
+			return nil, nil, err
+		}
+		rd, closer = zr, zr
+	}
+	return tar.NewReader(rd), closer, nil
+}
+
+// readTarEntry reads the contents of the entry at index in the tar archive r.
+func readTarEntry(r io.ReaderAt, size int64, compressed bool, index int) ([]byte, error) {
+	tr, closer, err := openTar(r, size, compressed)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	for i := 0; i <= index; i++ {
+		if _, err := tr.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(tr)
+}
+
+// add adds the entry at name to the tree of the node, creating the directories in its path
+// which are missing. It returns the node of the entry, or nil if the name is not valid.
+func (n *Node) add(name string, mode fs.FileMode, size int64, modTime time.Time) *Node {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" || name == "." {
+		return nil
+	}
+	parent := n
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		c := parent.child(s)
+		last := i == len(segments)-1
+		if c == nil {
+			c = &Node{parent: parent, name: s, path: strings.Join(segments[:i+1], "/")}
+			c.mode, c.implicit = fs.ModeDir|0o755, true
+			parent.children = append(parent.children, c)
+		}
+		if last {
+			c.mode, c.size, c.modTime, c.implicit = mode, size, modTime, false
+			if c.IsDir() {
+				c.size = 0
+			}
+		}
+		parent = c
+	}
+	return parent
+}
+
+func (n *Node) child(name string) *Node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// sort orders the children of the directories by name, and sets their initial state.
+func (n *Node) sort() {
+	slices.SortFunc(n.children, func(a, b *Node) int {
+		return strings.Compare(a.name, b.name)
+	})
+	for _, c := range n.children {
+		switch {
+		case c.open != nil:
+			c.state = tree.NodeCollapsible | tree.NodeCollapsed
+		case len(c.children) > 0:
+			c.state = tree.NodeCollapsible | tree.NodeCollapsed
+			c.sort()
+		}
+	}
+}
+
+// isArchive checks if the file name has the extension of a supported archive.
+func isArchive(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range []string{".zip", ".jar", ".war", ".whl", ".tar", ".tgz", ".tar.gz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load opens the nested archive of the node, and lists its contents.
+func (n *Node) Load() error {
+	if n.open == nil || n.loaded {
+		return n.err
+	}
+	n.loaded = true
+	data, err := n.open()
+	if err == nil {
+		err = n.list(bytes.NewReader(data), int64(len(data)))
+	}
+	if n.err = err; err != nil {
+		n.state &^= tree.NodeCollapsible
+	}
+	return err
+}
+
+// Name returns the name of the file.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Title returns the name of the file, to be used by the tree.Breadcrumb and tree.FileIcons.
+func (n *Node) Title() string {
+	return n.name
+}
+
+// Path returns the name of the entry in its archive, which is empty for the archive itself.
+func (n *Node) Path() string {
+	return n.path
+}
+
+// Size returns the uncompressed size of the file.
+func (n *Node) Size() int64 {
+	return n.size
+}
+
+// Mode returns the file mode and permission bits of the entry.
+func (n *Node) Mode() fs.FileMode {
+	return n.mode
+}
+
+// ModTime returns the modification time of the entry, which is zero for the directories
+// without an entry in the archive.
+func (n *Node) ModTime() time.Time {
+	return n.modTime
+}
+
+// IsDir reports whether the node is a directory of the archive.
+func (n *Node) IsDir() bool {
+	return n.mode.IsDir()
+}
+
+// IsImplicit reports whether the node is a directory without an entry in the archive.
+func (n *Node) IsImplicit() bool {
+	return n.implicit
+}
+
+// IsArchive reports whether the node is an archive, which can be expanded.
+func (n *Node) IsArchive() bool {
+	return n.parent == nil || n.open != nil
+}
+
+// Link returns the target of the symbolic or hard link, if the entry is one.
+func (n *Node) Link() string {
+	return n.link
+}
+
+// Err returns the error encountered when opening the nested archive.
+func (n *Node) Err() error {
+	return n.err
+}
+
+// Columns returns the mode, size and modification time columns, to be set in the tree Model.
+func Columns() []tree.Column {
+	return []tree.Column{
+		column(func(n *Node) string { return n.mode.String() }),
+		column(func(n *Node) string {
+			if n.IsDir() {
+				return fmt.Sprintf("%10s", "")
+			}
+			return fmt.Sprintf("%10s", size.Format(n.size))
+		}),
+		column(func(n *Node) string {
+			if n.modTime.IsZero() {
+				return fmt.Sprintf("%16s", "")
+			}
+			return n.modTime.Format("2006-01-02 15:04")
+		}),
+	}
+}
+
+// column renders the value of fn for the nodes of the archives, and nothing for the other nodes.
+func column(fn func(*Node) string) tree.Column {
+	return tree.ColumnFunc(func(n tree.Node) string {
+		if an, ok := n.(*Node); ok {
+			return fn(an)
+		}
+		return ""
+	})
+}
+
+func (n *Node) View() tea.View {
+	switch {
+	case n.err != nil:
+		return tea.NewView(n.name + ": " + n.err.Error())
+	case n.link != "":
+		return tea.NewView(n.name + " → " + n.link)
+	}
+	return tea.NewView(n.name)
+}
+
+func (n *Node) Parent() tree.Node {
+	if n == nil || n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Init() tea.Cmd {
+	return nil
+}
+
+func (n *Node) Children() tree.Nodes {
+	if len(n.children) == 0 {
+		return nil
+	}
+	nodes := make(tree.Nodes, len(n.children))
+	for i, c := range n.children {
+		nodes[i] = c
+	}
+	return nodes
+}
+
+func (n *Node) State() tree.NodeState {
+	return n.state
+}
+
+// Update sets the state of the node, and opens the nested archives when they are expanded for the first time.
+func (n *Node) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tree.NodeState:
+		n.state = m
+		if !m.Is(tree.NodeCollapsed) && !n.loaded {
+			_ = n.Load()
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,239 @@
+package archivetree
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+var mtime = time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+type file struct {
+	name string
+	data string
+	link string
+	dir  bool
+}
+
+func makeZip(t *testing.T, files ...file) []byte {
+	t.Helper()
+	buf := bytes.Buffer{}
+	w := zip.NewWriter(&buf)
+	for _, f := range files {
+		h := &zip.FileHeader{Name: f.name, Modified: mtime, Method: zip.Deflate}
+		h.SetMode(0o644)
+		if f.dir {
+			h.SetMode(os.ModeDir | 0o755)
+		}
+		fw, err := w.CreateHeader(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(f.data))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeTar(t *testing.T, compressed bool, files ...file) []byte {
+	t.Helper()
+	buf := bytes.Buffer{}
+	var gz *gzip.Writer
+	w := tar.NewWriter(&buf)
+	if compressed {
+		gz = gzip.NewWriter(&buf)
+		w = tar.NewWriter(gz)
+	}
+	for _, f := range files {
+		h := &tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.data)), ModTime: mtime, Typeflag: tar.TypeReg}
+		switch {
+		case f.dir:
+			h.Typeflag, h.Mode = tar.TypeDir, 0o755
+		case f.link != "":
+			h.Typeflag, h.Linkname = tar.TypeSymlink, f.link
+		}
+		if err := w.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(f.data))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		gz.Close()
+	}
+	return buf.Bytes()
+}
+
+// views returns the views of all the nodes, with the children of the expanded ones indented.
+func views(nodes tree.Nodes) []string {
+	result := make([]string, 0)
+	for _, n := range nodes {
+		result = append(result, n.View().Content)
+		if !n.State().Is(tree.NodeCollapsed) {
+			for _, c := range views(n.Children()) {
+				result = append(result, "  "+c)
+			}
+		}
+	}
+	return result
+}
+
+// expandAll expands all the nodes, opening the nested archives.
+func expandAll(n tree.Node) {
+	n.Update(n.State() &^ tree.NodeCollapsed)
+	for _, c := range n.Children() {
+		expandAll(c)
+	}
+}
+
+func TestNew(t *testing.T) {
+	inner := makeZip(t, file{name: "inner.txt", data: "inner"})
+	tests := []struct {
+		name string
+		data []byte
+		want []string
+	}{
+		{
+			name: "zip",
+			data: makeZip(t,
+				file{name: "bin/tool", data: "tool"},
+				file{name: "docs/", dir: true},
+				file{name: "README.md", data: "readme"},
+				file{name: "lib/inner.jar", data: string(inner)},
+			),
+			want: []string{"archive", "  README.md", "  bin", "    tool", "  docs", "  lib", "    inner.jar", "      inner.txt"},
+		},
+		{
+			name: "tar",
+			data: makeTar(t, false,
+				file{name: "./pkg/", dir: true},
+				file{name: "./pkg/a.txt", data: "a"},
+				file{name: "../escape.txt", data: "e"},
+				file{name: "pkg/link", link: "a.txt"},
+			),
+			want: []string{"archive", "  escape.txt", "  pkg", "    a.txt", "    link → a.txt"},
+		},
+		{
+			name: "tar.gz with nested tar",
+			data: makeTar(t, true,
+				file{name: "release/notes.txt", data: "notes"},
+				file{name: "release/src.tar", data: string(makeTar(t, false, file{name: "main.go", data: "package main"}))},
+				file{name: "release/broken.zip", data: "not a zip"},
+			),
+			want: []string{
+				"archive",
+				"  release",
+				"    broken.zip: " + ErrFormat.Error(),
+				"    notes.txt",
+				"    src.tar",
+				"      main.go",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := New("archive", bytes.NewReader(tt.data), int64(len(tt.data)))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			expandAll(n)
+			if diff := cmp.Diff(tt.want, views(tree.Nodes{n})); diff != "" {
+				t.Errorf("New() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	data := []byte("plain text")
+	if _, err := New("text", bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrFormat) {
+		t.Errorf("New() error = %v, want %v", err, ErrFormat)
+	}
+}
+
+func TestNode_lazy(t *testing.T) {
+	inner := makeTar(t, true, file{name: "a", data: "a"})
+	data := makeZip(t, file{name: "inner.tgz", data: string(inner)})
+	n, err := New("archive", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	nested := n.children[0]
+	if !nested.IsArchive() || !nested.State().Is(tree.NodeCollapsed|tree.NodeCollapsible) {
+		t.Fatalf("the nested archive should be collapsed, state = %v", nested.State())
+	}
+	if nested.loaded || len(nested.children) > 0 {
+		t.Errorf("the nested archive was opened before being expanded")
+	}
+	nested.Update(nested.State() &^ tree.NodeCollapsed)
+	if len(nested.children) != 1 || nested.children[0].Name() != "a" {
+		t.Errorf("the nested archive wasn't opened when expanded")
+	}
+}
+
+func TestNode_metadata(t *testing.T) {
+	data := makeTar(t, false, file{name: "dir/file.txt", data: "12345"})
+	n, err := New("archive", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	dir := n.children[0]
+	f := dir.children[0]
+	tests := []struct {
+		name string
+		node *Node
+		want []string
+	}{
+		{name: "implicit dir", node: dir, want: []string{"drwxr-xr-x", "          ", "                "}},
+		{name: "file", node: f, want: []string{"-rw-r--r--", "       5 B", "2024-05-01 12:30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, c := range Columns() {
+				got = append(got, c.Render(tt.node))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Columns() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if !dir.IsImplicit() || !dir.IsDir() || f.IsImplicit() {
+		t.Errorf("IsImplicit() = %v, %v", dir.IsImplicit(), f.IsImplicit())
+	}
+	if f.Path() != "dir/file.txt" || f.Size() != 5 || !f.ModTime().Equal(mtime) {
+		t.Errorf("metadata = %q %d %v", f.Path(), f.Size(), f.ModTime())
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release.zip")
+	if err := os.WriteFile(path, makeZip(t, file{name: "a.txt", data: "a"}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	n, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer n.Close()
+	want := []string{"release.zip", "  a.txt"}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("Open() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.zip")); err == nil {
+		t.Errorf("Open() of a missing file should fail")
+	}
+}
@@ -0,0 +1,295 @@
+package tree
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Titler is implemented by nodes which can provide a short label for themselves,
+// to be used where their full View doesn't fit, like in the Breadcrumb.
+type Titler interface {
+	Title() string
+}
+
+// DefaultSeparator is the string rendered between the segments of a Breadcrumb.
+const DefaultSeparator = " › "
+
+// GotoNodeMsg requests the tree Model to move its selection to Node.
+type GotoNodeMsg struct {
+	Node
+}
+
+func gotoNode(n Node) tea.Cmd {
+	return func() tea.Msg {
+		return GotoNodeMsg{Node: n}
+	}
+}
+
+// BreadcrumbKeyMap defines keybindings for the Breadcrumb.
+type BreadcrumbKeyMap struct {
+	Previous key.Binding
+	Next     key.Binding
+	Select   key.Binding
+}
+
+// DefaultBreadcrumbKeyMap returns a default set of keybindings for the Breadcrumb.
+func DefaultBreadcrumbKeyMap() BreadcrumbKeyMap {
+	return BreadcrumbKeyMap{
+		Previous: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous segment"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next segment"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "go to segment"),
+		),
+	}
+}
+
+// BreadcrumbStyles contains style definitions for the Breadcrumb.
+type BreadcrumbStyles struct {
+	Segment   lipgloss.Style
+	Current   lipgloss.Style
+	Selected  lipgloss.Style
+	Separator lipgloss.Style
+}
+
+// DefaultBreadcrumbStyles returns a set of default style definitions for the Breadcrumb.
+func DefaultBreadcrumbStyles() BreadcrumbStyles {
+	return BreadcrumbStyles{
+		Segment:   defaultStyle,
+		Current:   defaultStyle.Bold(true),
+		Selected:  defaultSelectedStyle,
+		Separator: defaultStyle.Faint(true),
+	}
+}
+
+// Breadcrumb is a Bubble Tea model which renders the path from the root of the tree
+// to its current node. It follows the cursor of the tree Model by handling the
+// messages the Model emits when the cursor changes position.
+//
+// When focused, the segments can be selected with the keyboard, and clicking on
+// them always works. Selecting a segment emits a GotoNodeMsg for the tree Model.
+type Breadcrumb struct {
+	KeyMap    BreadcrumbKeyMap
+	Styles    BreadcrumbStyles
+	Separator string
+
+	width    int
+	focus    bool
+	selected int
+
+	path Nodes
+	// spans holds the start and end columns of each path segment in the last render,
+	// which are empty for the segments that were elided.
+	spans [][2]int
+}
+
+// NewBreadcrumb initializes a new Breadcrumb for the node n, which can be nil.
+func NewBreadcrumb(n Node) *Breadcrumb {
+	b := &Breadcrumb{
+		KeyMap:    DefaultBreadcrumbKeyMap(),
+		Styles:    DefaultBreadcrumbStyles(),
+		Separator: DefaultSeparator,
+	}
+	b.SetNode(n)
+	return b
+}
+
+// SetNode sets the node for which the path is displayed.
+func (b *Breadcrumb) SetNode(n Node) {
+	if n == nil {
+		b.path = nil
+	} else {
+		b.path = append(ancestors(n), n)
+	}
+	b.selected = len(b.path) - 1
+}
+
+// Path returns the nodes of the path, from the root down to the current node.
+func (b *Breadcrumb) Path() Nodes {
+	return b.path
+}
+
+// SetWidth sets the maximum width of the Breadcrumb. Zero means unlimited.
+func (b *Breadcrumb) SetWidth(w int) {
+	b.width = w
+}
+
+// Width returns the maximum width of the Breadcrumb.
+func (b *Breadcrumb) Width() int {
+	return b.width
+}
+
+// Focused returns the focus state of the Breadcrumb.
+func (b *Breadcrumb) Focused() bool {
+	return b.focus
+}
+
+// Focus focuses the Breadcrumb, allowing the user to move between its segments.
+func (b *Breadcrumb) Focus() {
+	b.focus = true
+}
+
+// Blur blurs the Breadcrumb, the last segment becoming the selected one again.
+func (b *Breadcrumb) Blur() {
+	b.focus = false
+	b.selected = len(b.path) - 1
+}
+
+// Select emits the message for moving the tree selection to the segment at index i.
+func (b *Breadcrumb) Select(i int) tea.Cmd {
+	if i < 0 || i >= len(b.path) {
+		return noop
+	}
+	b.selected = i
+	return gotoNode(b.path[i])
+}
+
+func (b *Breadcrumb) Init() tea.Cmd {
+	return nil
+}
+
+// Update follows the tree Model position changes and handles the segment selection.
+func (b *Breadcrumb) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch mm := msg.(type) {
+	case Node:
+		b.SetNode(mm)
+	case tea.WindowSizeMsg:
+		b.SetWidth(mm.Width)
+	case tea.MouseClickMsg:
+		cmd = b.click(mm.Mouse())
+	case tea.KeyPressMsg:
+		if !b.focus {
+			break
+		}
+		switch {
+		case key.Matches(mm, b.KeyMap.Previous):
+			b.selected = clamp(b.selected-1, 0, len(b.path)-1)
+		case key.Matches(mm, b.KeyMap.Next):
+			b.selected = clamp(b.selected+1, 0, len(b.path)-1)
+		case key.Matches(mm, b.KeyMap.Select):
+			cmd = b.Select(b.selected)
+		}
+	}
+	return b, cmd
+}
+
+func (b *Breadcrumb) click(mouse tea.Mouse) tea.Cmd {
+	if mouse.Button != tea.MouseLeft || mouse.Y != 0 {
+		return noop
+	}
+	for i, sp := range b.spans {
+		if mouse.X >= sp[0] && mouse.X < sp[1] {
+			return b.Select(i)
+		}
+	}
+	return noop
+}
+
+// View renders the path segments separated by the Separator. When they don't fit in the width
+// of the Breadcrumb, the segments after the root are replaced with an Ellipsis.
+func (b *Breadcrumb) View() tea.View {
+	b.spans = make([][2]int, len(b.path))
+	if len(b.path) == 0 {
+		return tea.NewView("")
+	}
+
+	labels := make([]string, len(b.path))
+	for i, n := range b.path {
+		labels[i] = title(n)
+	}
+
+	out := strings.Builder{}
+	col := 0
+	sep := b.Styles.Separator.Render(b.Separator)
+	for j, i := range b.visibleSegments(labels) {
+		if j > 0 {
+			out.WriteString(sep)
+			col += ansi.StringWidth(b.Separator)
+		}
+		if i < 0 {
+			out.WriteString(b.Styles.Segment.Render(Ellipsis))
+			col += ansi.StringWidth(Ellipsis)
+			continue
+		}
+
+		style := b.Styles.Segment
+		if i == len(b.path)-1 {
+			style = b.Styles.Current
+		}
+		if b.focus && i == b.selected {
+			style = b.Styles.Selected
+		}
+		out.WriteString(style.Render(labels[i]))
+		b.spans[i] = [2]int{col, col + ansi.StringWidth(labels[i])}
+		col = b.spans[i][1]
+	}
+
+	view := out.String()
+	if b.width > 0 && ansi.StringWidth(view) > b.width {
+		view = ansi.Truncate(view, b.width, Ellipsis)
+	}
+	return tea.NewView(view)
+}
+
+// visibleSegments returns the indexes of the labels that fit in the Breadcrumb width,
+// with -1 marking the position where the elided segments were.
+// The first and the last segment are always kept.
+func (b *Breadcrumb) visibleSegments(labels []string) []int {
+	segments := make([]int, len(labels))
+	for i := range labels {
+		segments[i] = i
+	}
+	if b.width <= 0 {
+		return segments
+	}
+
+	sepWidth := ansi.StringWidth(b.Separator)
+	total := func() int {
+		w := 0
+		for j, i := range segments {
+			if j > 0 {
+				w += sepWidth
+			}
+			if i < 0 {
+				w += ansi.StringWidth(Ellipsis)
+			} else {
+				w += ansi.StringWidth(labels[i])
+			}
+		}
+		return w
+	}
+
+	for total() > b.width {
+		switch {
+		case len(segments) > 2 && segments[1] >= 0:
+			segments[1] = -1
+		case len(segments) > 3:
+			segments = append(segments[:2], segments[3:]...)
+		default:
+			return segments
+		}
+	}
+	return segments
+}
+
+// title returns the short label of the node n: its Title if it implements Titler,
+// or the first line of its View, stripped of styling, otherwise.
+func title(n Node) string {
+	if t, ok := n.(Titler); ok {
+		return t.Title()
+	}
+	first, _, _ := strings.Cut(ansi.Strip(n.View().Content), "\n")
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,137 @@
+package tree
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type titled struct {
+	*n
+	title string
+}
+
+func (t titled) Title() string {
+	return t.title
+}
+
+func Test_title(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+		want string
+	}{
+		{
+			name: "plain",
+			node: tn("test"),
+			want: "test",
+		},
+		{
+			name: "styled multi line",
+			node: tn(lipgloss.NewStyle().Bold(true).Render("first") + "\nsecond"),
+			want: "first",
+		},
+		{
+			name: "titler",
+			node: titled{n: tn("some long content"), title: "short"},
+			want: "short",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := title(tt.node); got != tt.want {
+				t.Errorf("title() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadcrumb_View(t *testing.T) {
+	tree := newTreeOne()
+	file := tree.c[1].c[0].c[2].c[0]
+	tests := []struct {
+		name  string
+		node  Node
+		width int
+		want  string
+	}{
+		{
+			name: "nil",
+			node: nil,
+			want: "",
+		},
+		{
+			name: "root",
+			node: tree,
+			want: "tmp",
+		},
+		{
+			name: "unlimited width",
+			node: file,
+			want: "tmp / test / example / lastchild / file",
+		},
+		{
+			name:  "elided middle",
+			node:  file,
+			width: 36,
+			want:  "tmp / … / example / lastchild / file",
+		},
+		{
+			name:  "elided middle - multiple",
+			node:  file,
+			width: 20,
+			want:  "tmp / … / file",
+		},
+		{
+			name:  "truncated",
+			node:  file,
+			width: 5,
+			want:  "tmp …",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBreadcrumb(tt.node)
+			b.Separator = " / "
+			b.SetWidth(tt.width)
+			if got := ansi.Strip(b.View().Content); got != tt.want {
+				t.Errorf("View() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadcrumb_Update(t *testing.T) {
+	tree := newTreeOne()
+	example := tree.c[1].c[0]
+
+	b := NewBreadcrumb(nil)
+	b.Separator = " / "
+	b.Update(Node(example))
+	if got := len(b.Path()); got != 3 {
+		t.Fatalf("Path() length = %d, want %d", got, 3)
+	}
+	b.View()
+
+	_, cmd := b.Update(tea.MouseClickMsg{X: 7, Y: 0, Button: tea.MouseLeft})
+	if cmd == nil {
+		t.Fatalf("Update() click did not return a command")
+	}
+	msg, ok := cmd().(GotoNodeMsg)
+	if !ok {
+		t.Fatalf("Update() click returned %T, want %T", cmd(), GotoNodeMsg{})
+	}
+	if msg.Node != tree.c[1] {
+		t.Errorf("Update() click selected %s, want %s", msg.Node.View().Content, "test")
+	}
+
+	m := mockModel(tree)
+	m.SetWidth(30)
+	m.SetHeight(12)
+	m.Update(msg)
+	if got := m.currentNode(); got != tree.c[1] {
+		t.Errorf("Model did not move to the selected segment, current node %s", got.View().Content)
+	}
+}
@@ -0,0 +1,65 @@
+package tree
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Column renders additional information about the nodes, like their sizes or dates, in a column
+// at the left of the tree symbols. Every column is as wide as its widest value, and is separated
+// by a space from the next one.
+type Column interface {
+	Render(Node) string
+}
+
+// ColumnFunc is an adapter to allow the use of ordinary functions as Column.
+type ColumnFunc func(Node) string
+
+// Render returns f(n).
+func (f ColumnFunc) Render(n Node) string {
+	return f(n)
+}
+
+// updateColumnWidths computes the widths of the columns from their widest value in nodes,
+// including the gap after them. The columns without any value have zero width.
+func (m *Model) updateColumnWidths(nodes Nodes) {
+	widths := make([]int, len(m.Columns))
+	for i, c := range m.Columns {
+		w := 0
+		for _, n := range nodes {
+			w = max(w, ansi.StringWidth(c.Render(n)))
+		}
+		if w > 0 {
+			widths[i] = w + 1
+		}
+	}
+	m.columnWidths = widths
+}
+
+// leadingWidth returns the width of the columns rendered at the left of the tree symbols.
+func (m *Model) leadingWidth() int {
+	w := 0
+	for _, cw := range m.columnWidths {
+		w += cw
+	}
+	return w
+}
+
+// renderColumns renders the columns for node n, spanning lineCount lines.
+// Only the first line contains the values.
+func (m *Model) renderColumns(n Node, lineCount int) string {
+	lines := make([]string, max(1, lineCount))
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", m.leadingWidth())
+	}
+	first := strings.Builder{}
+	for i, c := range m.Columns {
+		if i >= len(m.columnWidths) || m.columnWidths[i] == 0 {
+			continue
+		}
+		first.WriteString(m.Styles.Column.Width(m.columnWidths[i]).Render(c.Render(n)))
+	}
+	lines[0] = first.String()
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,51 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModel_RenderAll_columns(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	depth := ColumnFunc(func(n Node) string {
+		return strings.Repeat("*", getDepth(n))
+	})
+	empty := ColumnFunc(func(Node) string { return "" })
+
+	want := []string{
+		"     └─ tmp",
+		"*       ├─ example1",
+		"*       └─ test",
+		"**         ├─ example",
+		"***        │  ├─ file2",
+		"***        │  ├─ file4",
+		"***        │  └─ lastchild",
+		"****       │     └─ file",
+		"**         ├─ file1",
+		"**         ├─ file3",
+		"**         └─ file5",
+	}
+	got := strings.Split(m.RenderAll(WithColumns(depth, empty)), "\n")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RenderAll() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestModel_onExpander_columns(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.ShowExpander = true
+	m.Columns = []Column{ColumnFunc(func(Node) string { return "ab" })}
+	m.updateColumnWidths(m.tree.sequentialNodes())
+
+	// 3 columns for "ab ", 3 for the root tree symbols
+	if m.onExpander(tree, 3) {
+		t.Errorf("onExpander() on the tree symbols = true")
+	}
+	if !m.onExpander(tree, 6) {
+		t.Errorf("onExpander() on the expander = false")
+	}
+}
@@ -170,16 +170,21 @@ func main() {
 	t.Symbols = tree.ThickEdgeSymbols()
 	t.Styles.Selected = t.Styles.Line
 
+	colors := []color.Color{
+		lipgloss.Color("#ff0000"),
+		lipgloss.Color("#00ff00"),
+		lipgloss.Color("#0000ff"),
+		lipgloss.Color("#00ffff"),
+		lipgloss.Color("#ff00ff"),
+		lipgloss.Color("#ffff00"),
+	}
 	t.Styles.Symbol = depthStyle{
-		Style: lipgloss.NewStyle(),
-		colors: []color.Color{
-			lipgloss.Color("#ff0000"),
-			lipgloss.Color("#00ff00"),
-			lipgloss.Color("#0000ff"),
-			lipgloss.Color("#00ffff"),
-			lipgloss.Color("#ff00ff"),
-			lipgloss.Color("#ffff00"),
-		},
+		Style:  lipgloss.NewStyle().Faint(true),
+		colors: colors,
+	}
+	t.Styles.ActiveSymbol = depthStyle{
+		Style:  lipgloss.NewStyle().Bold(true),
+		colors: colors,
 	}
 	m := quittingTree{Model: t}
 
@@ -3,182 +3,48 @@ package main
 import (
 	"flag"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
 	"path/filepath"
-	"slices"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/BurntSushi/toml"
 	tree "github.com/mariusor/bubbles-tree"
+	"github.com/mariusor/bubbles-tree/fstree"
 )
 
 const RootPath = "../../"
 
-type pathNode struct {
-	parent   *pathNode
-	path     string
-	state    tree.NodeState
-	children []*pathNode
-}
-
-func (n *pathNode) Parent() tree.Node {
-	if n == nil || n.parent == nil {
-		return nil
-	}
-	return n.parent
-}
-
-func (n *pathNode) Init() tea.Cmd {
-	return nil
-}
-
-func (n *pathNode) Path() string {
-	pieces := make([]string, 0)
-	parent := n
-	for {
-		if parent == nil {
-			break
-		}
-		pieces = append(pieces, parent.path)
-		parent = parent.parent
-	}
-	slices.Reverse(pieces)
-	return filepath.Join(pieces...)
-}
-
 const (
 	Collapsed = "⊞"
 	Expanded  = "⊟"
 )
 
-func (n *pathNode) View() tea.View {
-	name := filepath.Base(n.path)
-	if n.parent == nil {
-		name = n.path
-	}
-
-	hints := n.state
-	annotation := ""
-	s := strings.Builder{}
-	if hints&tree.NodeCollapsible == tree.NodeCollapsible {
-		annotation = Expanded
-		if hints&tree.NodeCollapsed == tree.NodeCollapsed {
-			annotation = Collapsed
-		}
-	}
-	if len(annotation) > 0 {
-		_, _ = fmt.Fprintf(&s, "%-2s%s", annotation, name)
-	} else {
-		_, _ = fmt.Fprintf(&s, "%s", name)
-	}
-
-	return tea.NewView(s.String())
-}
-
-func (n *pathNode) Children() tree.Nodes {
-	return treeNodes(n.children)
-}
-
-func (n *pathNode) State() tree.NodeState {
-	return n.state
-}
-
-func (n *pathNode) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch m := msg.(type) {
-	case tree.NodeState:
-		n.state = m
-	}
+type quittingTree struct {
+	*tree.Model
 
-	return n, nil
-}
+	// header shows the path to the node the tree is hoisted into.
+	header *tree.Breadcrumb
+	height int
 
-func (n *pathNode) setChildren(nodes ...*pathNode) {
-	n.children = n.children[:0]
-	for _, nn := range nodes {
-		nn.parent = n
-		n.children = append(n.children, nn)
-	}
+	watcher *fstree.Watcher
+	du      *fstree.DiskUsage
+	scanned fstree.ScanMsg
 }
 
-func isUnixHiddenFile(name string) bool {
-	return len(name) > 2 && name[0] == '.'
-}
-
-func buildPathNodes(root string) []*pathNode {
-	root = filepath.Clean(root)
-
-	allNodes := make([]*pathNode, 0)
-	rootPath := func(p string) string {
-		if p == "." {
-			return root
-		}
-		return p
+func (e *quittingTree) Init() tea.Cmd {
+	cmds := []tea.Cmd{e.Model.Init()}
+	if e.watcher != nil {
+		cmds = append(cmds, e.watcher.Init())
 	}
-	_ = fs.WalkDir(os.DirFS(root), ".", func(p string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return fs.SkipDir
-		}
-		if isUnixHiddenFile(d.Name()) {
-			if d.IsDir() {
-				return fs.SkipDir
-			}
-			return nil
-		}
-
-		cnt := len(strings.Split(p, string(os.PathSeparator)))
-		if cnt > 1 {
-			return fs.SkipDir
-		}
-
-		st := tree.NodeNone
-		if d.IsDir() {
-			st |= tree.NodeCollapsible
-		}
-		p = rootPath(p)
-		parent := findNodeByPath(allNodes, rootPath(filepath.Dir(p)))
-
-		node := new(pathNode)
-		node.path = p
-		node.state = st
-		node.children = make([]*pathNode, 0)
-
-		if parent == nil {
-			allNodes = append(allNodes, node)
-		} else {
-			node.parent = parent
-			node.state |= tree.NodeCollapsed
-			parent.children = append(parent.children, node)
-		}
-		return nil
-	})
-
-	return allNodes
-}
-
-func treeNodes(pathNodes []*pathNode) tree.Nodes {
-	nodes := make(tree.Nodes, len(pathNodes))
-	for i, n := range pathNodes {
-		nodes[i] = n
+	if e.du != nil {
+		cmds = append(cmds, e.du.Scan())
 	}
-	return nodes
-}
-
-func findNodeByPath(nodes []*pathNode, path string) *pathNode {
-	for _, node := range nodes {
-		if filepath.Clean(node.path) == filepath.Clean(path) {
-			return node
-		}
-		if child := findNodeByPath(node.children, path); child != nil {
-			return child
-		}
-	}
-	return nil
-}
-
-type quittingTree struct {
-	*tree.Model
+	return tea.Batch(cmds...)
 }
 
 func (e *quittingTree) Update(m tea.Msg) (tea.Model, tea.Cmd) {
@@ -187,26 +53,80 @@ func (e *quittingTree) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("q"))):
 			return e, tea.Quit
+		case e.du != nil && key.Matches(msg, key.NewBinding(key.WithKeys("s"))):
+			return e, e.du.SortBySize(!e.du.Sorted())
 		}
-	case tree.ExpandedMsg:
-		if n, ok := msg.Node.(*pathNode); ok {
-			it := buildPathNodes(n.Path())
-			if len(it) > 0 {
-				n.setChildren(it[0].children...)
-				return e, nil
-			}
+	case fstree.ScanMsg:
+		e.scanned = msg
+	case tea.WindowSizeMsg:
+		e.height = msg.Height
+		if e.header != nil {
+			e.header.SetWidth(msg.Width)
+			msg.Height--
+		}
+		m = msg
+	case tree.HoistedMsg:
+		e.header = nil
+		e.Model.SetHeight(e.height)
+		if msg.Node != nil {
+			e.header = tree.NewBreadcrumb(msg.Node)
+			e.header.SetWidth(e.Model.Width())
+			e.Model.SetHeight(e.height - 1)
 		}
 	}
+	var watchCmd, duCmd tea.Cmd
+	if e.watcher != nil {
+		watchCmd = e.watcher.Update(m)
+	}
+	if e.du != nil {
+		duCmd = e.du.Update(m)
+	}
 	mod, cmd := e.Model.Update(m)
 	if mm, ok := mod.(*tree.Model); ok {
 		e.Model = mm
 	}
-	return e, cmd
+	return e, tea.Batch(cmd, watchCmd, duCmd)
+}
+
+func (e *quittingTree) View() tea.View {
+	v := e.Model.View()
+	if e.header != nil {
+		v.SetContent(e.header.View().Content + "\n" + v.Content)
+	}
+	if e.du != nil && e.du.Scanning() {
+		v.WindowTitle = fmt.Sprintf("scanning: %d files, %s", e.scanned.Files, fstree.FormatSize(e.scanned.Size))
+	}
+	return v
+}
+
+// themeDecoder returns the decoder for the theme file at path, based on its extension.
+func themeDecoder(path string) tree.ThemeDecoder {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return func(r io.Reader, v any) error {
+			_, err := toml.NewDecoder(r).Decode(v)
+			return err
+		}
+	}
+	return tree.DecodeJSON
 }
 
 func main() {
 	var style string
-	flag.StringVar(&style, "style", "normal", "The style to use when drawing the tree: double, thick, rounded, edge, normal")
+	var printOnly bool
+	var icons string
+	var theme string
+	var hidden, gitIgnore, follow bool
+	var watch time.Duration
+	var du bool
+	flag.StringVar(&style, "style", "normal", "The style to use when drawing the tree: double, thick, rounded, edge, ascii, auto, normal")
+	flag.BoolVar(&printOnly, "print", false, "Print the tree to the standard output and exit")
+	flag.StringVar(&icons, "icons", "", "The icons to show for files and directories: nerd, unicode")
+	flag.StringVar(&theme, "theme", "", "The theme to use: default, solarized, nord, high-contrast, or the path to a JSON or TOML theme file")
+	flag.BoolVar(&hidden, "hidden", false, "Show the hidden files and directories")
+	flag.BoolVar(&gitIgnore, "gitignore", false, "Exclude the files matching the patterns in .gitignore files")
+	flag.BoolVar(&follow, "follow", false, "Follow the symbolic links to directories")
+	flag.DurationVar(&watch, "watch", 0, "Refresh the expanded directories at this interval, zero disables watching")
+	flag.BoolVar(&du, "du", false, "Show the disk usage of the files and directories, press 's' to sort them by size")
 	flag.Parse()
 
 	symbols := tree.DefaultSymbols()
@@ -221,11 +141,41 @@ func main() {
 		symbols = tree.NormalEdgeSymbols()
 	case "thickedge":
 		symbols = tree.ThickEdgeSymbols()
+	case "ascii":
+		symbols = tree.ASCIISymbols()
+	case "auto":
+		symbols = tree.DetectSymbols()
 	case "", "normal":
 	default:
 		_, _ = fmt.Fprintf(os.Stderr, "invalid style type, using default 'normal'\n")
 	}
 
+	symbols.Expanded = Expanded
+	symbols.Collapsed = Collapsed
+
+	var iconProvider tree.IconProvider
+	switch icons {
+	case "nerd":
+		iconProvider = tree.FileIcons{Set: tree.NerdFontIcons}
+	case "unicode":
+		iconProvider = tree.FileIcons{Set: tree.UnicodeIcons}
+	case "":
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "invalid icons type, not showing icons\n")
+	}
+
+	th := tree.DefaultTheme()
+	if theme != "" {
+		var ok bool
+		if th, ok = tree.Themes[theme]; !ok {
+			var err error
+			if th, err = tree.LoadTheme(theme, themeDecoder(theme)); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+				os.Exit(1)
+			}
+		}
+	}
+
 	path := RootPath
 	if flag.NArg() > 0 {
 		abs, err := filepath.Abs(flag.Arg(0))
@@ -236,9 +186,43 @@ func main() {
 		path = abs
 	}
 
-	t := tree.New(treeNodes(buildPathNodes(path)))
+	opts := []fstree.Option{fstree.WithName(filepath.Clean(path))}
+	if hidden {
+		opts = append(opts, fstree.ShowHidden())
+	}
+	if gitIgnore {
+		opts = append(opts, fstree.GitIgnore())
+	}
+	if follow {
+		opts = append(opts, fstree.FollowSymlinks())
+	}
+	root, err := fstree.Dir(path, opts...)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+
+	if printOnly {
+		if _, err := tree.Fprint(os.Stdout, tree.Nodes{root}, tree.WithSymbols(symbols), tree.WithStyles(th.Styles(lipgloss.HasDarkBackground(os.Stdin, os.Stdout))), tree.WithExpander(), tree.WithIcons(iconProvider)); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	t := tree.New(tree.Nodes{root})
 	t.Symbols = symbols
+	t.SetTheme(th)
+	t.ShowExpander = true
+	t.Icons = iconProvider
 	m := quittingTree{Model: t}
+	if watch > 0 {
+		m.watcher = fstree.NewWatcher(root, watch)
+	}
+	if du {
+		m.du = fstree.NewDiskUsage(root)
+		t.Columns = []tree.Column{m.du}
+	}
 
 	if _, err := tea.NewProgram(&m).Run(); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err.Error())
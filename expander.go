@@ -0,0 +1,65 @@
+package tree
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// expanderSymbols returns the markers for the expanded, collapsed, leaf and loading nodes.
+func (s Symbols) expanderSymbols() (string, string, string, string) {
+	or := func(v, def string) string {
+		if v == "" {
+			return def
+		}
+		return v
+	}
+	return or(s.Expanded, DefaultExpanded), or(s.Collapsed, DefaultCollapsed),
+		or(s.Leaf, DefaultLeaf), or(s.Loading, DefaultLoading)
+}
+
+// expanderWidth returns the width of the expander column, including the gap before the content.
+func (m *Model) expanderWidth() int {
+	if !m.ShowExpander {
+		return 0
+	}
+	ex, co, le, lo := m.Symbols.expanderSymbols()
+	return max(ansi.StringWidth(ex), ansi.StringWidth(co), ansi.StringWidth(le), ansi.StringWidth(lo)) + 1
+}
+
+// expanderSymbol returns the marker corresponding to the state of node n.
+func (m *Model) expanderSymbol(n Node) string {
+	ex, co, le, lo := m.Symbols.expanderSymbols()
+	switch {
+	case isLoading(n):
+		return lo
+	case isCollapsible(n) || len(n.Children()) > 0:
+		if isExpanded(n) {
+			return ex
+		}
+		return co
+	default:
+		return le
+	}
+}
+
+// renderExpander renders the expander column for node n, spanning lineCount lines.
+// Only the first line contains the marker.
+func (m *Model) renderExpander(n Node, lineCount int) string {
+	w := m.expanderWidth()
+	lines := make([]string, max(1, lineCount))
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", w)
+	}
+	lines[0] = m.Styles.Expander.Width(w).Render(m.expanderSymbol(n))
+	return strings.Join(lines, "\n")
+}
+
+// onExpander checks if the column x falls on the expander of node n.
+func (m *Model) onExpander(n Node, x int) bool {
+	if !m.ShowExpander {
+		return false
+	}
+	start := m.leadingWidth() + (m.depth(n)+1)*width(m.Symbols)
+	return x >= start && x < start+m.expanderWidth()
+}
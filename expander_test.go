@@ -0,0 +1,99 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func expanderTree() *n {
+	return tn("one", st(NodeLastChild), c(
+		tn("two", st(NodeCollapsed), c(tn("three"))),
+		tn("four", st(NodeLoading)),
+		tn("five"),
+	))
+}
+
+func TestModel_RenderAll_expander(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols Symbols
+		want    string
+	}{
+		{
+			name:    "default",
+			symbols: NormalSymbols(),
+			want: "└─ ▾ one\n" +
+				"   ├─ ▸ two\n" +
+				"   ├─ ⋯ four\n" +
+				"   └─   five",
+		},
+		{
+			name:    "ascii",
+			symbols: ASCIISymbols(),
+			want: "`-- - one\n" +
+				"    |-- + two\n" +
+				"    |-- ~ four\n" +
+				"    `--   five",
+		},
+		{
+			name: "wide markers",
+			symbols: Symbols{
+				Starter: "├─", Connector: "│", Terminator: "└─",
+				Expanded: "⊟⊟", Collapsed: "⊞",
+			},
+			want: "└─ ⊟⊟ one\n" +
+				"   ├─ ⊞  two\n" +
+				"   ├─ ⋯  four\n" +
+				"   └─    five",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockModel(expanderTree())
+			got := m.RenderAll(WithSymbols(tt.symbols), WithExpander())
+			if got != tt.want {
+				t.Errorf("RenderAll() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_renderRow_expanderMultiLine(t *testing.T) {
+	m := mockModel()
+	m.ShowExpander = true
+	node := tn("first\nsecond", st(NodeLastChild))
+	m.tree = Nodes{node}
+
+	lines := strings.Split(m.renderRow(node), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("renderRow() line count = %d, want %d", len(lines), 2)
+	}
+	if want := "  second"; !strings.HasSuffix(lines[1], want) {
+		t.Errorf("renderRow() second line = %q, want suffix %q", lines[1], want)
+	}
+}
+
+func TestModel_click_expander(t *testing.T) {
+	tree := expanderTree()
+	m := mockModel(tree)
+	m.ShowExpander = true
+	m.SetWidth(30)
+	m.SetHeight(5)
+	m.View()
+
+	// the content of "two" is not on the expander
+	m.click(tea.Mouse{X: 10, Y: 1, Button: tea.MouseLeft})
+	if !tree.c[0].State().Is(NodeCollapsed) {
+		t.Errorf("click() on the content changed the collapsed state")
+	}
+	if got := m.currentNode(); got != tree.c[0] {
+		t.Errorf("click() on the content did not select the node")
+	}
+	// the expander of "two" starts after the symbols of its depth
+	m.click(tea.Mouse{X: 6, Y: 1, Button: tea.MouseLeft})
+	if tree.c[0].State().Is(NodeCollapsed) {
+		t.Errorf("click() on the expander did not expand the node")
+	}
+}
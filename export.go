@@ -0,0 +1,143 @@
+package tree
+
+import (
+	"encoding/json"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Exportable is implemented by nodes which provide structured content for the exporters,
+// to be used instead of the styled output of their View.
+type Exportable interface {
+	// Text returns the plain text representation of the node.
+	Text() string
+	// Fields returns additional data about the node, which is included in the JSON export.
+	Fields() map[string]any
+}
+
+// ExportedState holds the state flags of an exported node.
+type ExportedState struct {
+	Collapsible bool `json:"collapsible,omitempty"`
+	Collapsed   bool `json:"collapsed,omitempty"`
+	Selected    bool `json:"selected,omitempty"`
+	Marked      bool `json:"marked,omitempty"`
+}
+
+// ExportedNode is the representation of a node used by the exporters.
+type ExportedNode struct {
+	Text     string         `json:"text"`
+	Depth    int            `json:"depth"`
+	State    ExportedState  `json:"state"`
+	Fields   map[string]any `json:"fields,omitempty"`
+	Children []ExportedNode `json:"children,omitempty"`
+}
+
+// Export converts the nodes, and all their descendants, regardless of their collapsed state,
+// to ExportedNode values. The hidden nodes are skipped.
+func Export(nodes Nodes) []ExportedNode {
+	return exportNodes(nodes, 0)
+}
+
+func exportNodes(nodes Nodes, depth int) []ExportedNode {
+	result := make([]ExportedNode, 0, len(nodes))
+	for _, n := range nodes {
+		if n == nil || isHidden(n) {
+			continue
+		}
+		result = append(result, exportNode(n, depth))
+	}
+	return result
+}
+
+func exportNode(n Node, depth int) ExportedNode {
+	e := ExportedNode{Depth: depth}
+	if ex, ok := n.(Exportable); ok {
+		e.Text = ex.Text()
+		e.Fields = ex.Fields()
+	} else {
+		e.Text = strings.TrimRight(ansi.Strip(n.View().Content), "\n ")
+	}
+
+	children := n.Children()
+	collapsible := isCollapsible(n) || len(children) > 0
+	e.State = ExportedState{
+		Collapsible: collapsible,
+		Collapsed:   collapsible && !isExpanded(n),
+		Selected:    isSelected(n),
+		Marked:      isMarked(n),
+	}
+	if len(children) > 0 {
+		e.Children = exportNodes(children, depth+1)
+	}
+	return e
+}
+
+// ExportJSON writes the nodes to w as a JSON array of ExportedNode objects.
+func ExportJSON(w io.Writer, nodes Nodes) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Export(nodes))
+}
+
+// ExportMarkdown writes the nodes to w as a nested Markdown list. The characters of the node
+// text which have a meaning in Markdown, like "*" or "[", are escaped with a backslash.
+func ExportMarkdown(w io.Writer, nodes Nodes) error {
+	out := strings.Builder{}
+	writeMarkdown(&out, Export(nodes))
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+func writeMarkdown(out *strings.Builder, nodes []ExportedNode) {
+	for _, n := range nodes {
+		indent := strings.Repeat("  ", n.Depth)
+		lines := strings.Split(markdownEscaper.Replace(n.Text), "\n")
+		out.WriteString(indent + "- " + lines[0] + "\n")
+		for _, l := range lines[1:] {
+			out.WriteString(indent + "  " + l + "\n")
+		}
+		writeMarkdown(out, n.Children)
+	}
+}
+
+// ExportHTML writes the nodes to w as an HTML fragment of nested lists. The nodes with
+// children are rendered as <details> elements, which are open if the node is expanded.
+//
+// The output is not a complete document: it has no doctype, <head> or <body>, and it is
+// meant to be included in a page, like a bug report or a documentation page.
+func ExportHTML(w io.Writer, nodes Nodes) error {
+	out := strings.Builder{}
+	writeHTML(&out, Export(nodes), 0)
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func writeHTML(out *strings.Builder, nodes []ExportedNode, depth int) {
+	if len(nodes) == 0 {
+		return
+	}
+	indent := strings.Repeat("  ", depth*2)
+	out.WriteString(indent + "<ul>\n")
+	for _, n := range nodes {
+		text := strings.ReplaceAll(html.EscapeString(n.Text), "\n", "<br>")
+		if len(n.Children) == 0 {
+			out.WriteString(indent + "  <li>" + text + "</li>\n")
+			continue
+		}
+		open := " open"
+		if n.State.Collapsed {
+			open = ""
+		}
+		out.WriteString(indent + "  <li><details" + open + "><summary>" + text + "</summary>\n")
+		writeHTML(out, n.Children, depth+1)
+		out.WriteString(indent + "  </details></li>\n")
+	}
+	out.WriteString(indent + "</ul>\n")
+}
@@ -0,0 +1,115 @@
+package tree
+
+import (
+	"bytes"
+	"testing"
+)
+
+type exportable struct {
+	*n
+}
+
+func (e exportable) Text() string {
+	return "plain " + e.n.n
+}
+
+func (e exportable) Fields() map[string]any {
+	return map[string]any{"size": 42}
+}
+
+func exportTree() Nodes {
+	return Nodes{
+		tn("root <1>", st(NodeLastChild), c(
+			tn("one", st(NodeCollapsed), mk(), c(tn("two\nlines"))),
+			tn("`three` *[x]*"),
+		)),
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	want := `- root \<1\>
+  - one
+    - two
+      lines
+  - \` + "`" + `three\` + "`" + ` \*\[x\]\*
+`
+	buf := bytes.Buffer{}
+	if err := ExportMarkdown(&buf, exportTree()); err != nil {
+		t.Fatalf("ExportMarkdown() error = %s", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("ExportMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	want := `<ul>
+  <li><details open><summary>root &lt;1&gt;</summary>
+    <ul>
+      <li><details><summary>one</summary>
+        <ul>
+          <li>two<br>lines</li>
+        </ul>
+      </details></li>
+      <li>` + "`three`" + ` *[x]*</li>
+    </ul>
+  </details></li>
+</ul>
+`
+	buf := bytes.Buffer{}
+	if err := ExportHTML(&buf, exportTree()); err != nil {
+		t.Fatalf("ExportHTML() error = %s", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("ExportHTML() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	want := `[
+  {
+    "text": "root",
+    "depth": 0,
+    "state": {
+      "collapsible": true
+    },
+    "children": [
+      {
+        "text": "plain one",
+        "depth": 1,
+        "state": {
+          "marked": true
+        },
+        "fields": {
+          "size": 42
+        }
+      }
+    ]
+  }
+]
+`
+	root := tn("root", st(NodeLastChild))
+	// a leaf is never reported as collapsed
+	one := tn("one", st(NodeLastChild|NodeCollapsed), mk())
+	root.c = []*n{one}
+	one.p = root
+	nodes := Nodes{exportableRoot{n: root, child: exportable{n: one}}}
+
+	buf := bytes.Buffer{}
+	if err := ExportJSON(&buf, nodes); err != nil {
+		t.Fatalf("ExportJSON() error = %s", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("ExportJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// exportableRoot wraps its only child as an exportable node.
+type exportableRoot struct {
+	*n
+	child Node
+}
+
+func (e exportableRoot) Children() Nodes {
+	return Nodes{e.child}
+}
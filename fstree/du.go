@@ -0,0 +1,305 @@
+package fstree
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	tree "github.com/mariusor/bubbles-tree"
+	"github.com/mariusor/bubbles-tree/internal/size"
+)
+
+// DefaultBarWidth is the width of the percentage bar rendered by DiskUsage.
+const DefaultBarWidth = 10
+
+// scanProgressInterval is the minimum time between two progress updates of a scan.
+const scanProgressInterval = 100 * time.Millisecond
+
+// Usage is the disk usage of a file, or of all the files in a directory.
+type Usage struct {
+	Size  int64
+	Files int
+}
+
+func (u Usage) add(o Usage) Usage {
+	return Usage{Size: u.Size + o.Size, Files: u.Files + o.Files}
+}
+
+// ScanMsg reports the progress of the scan started by DiskUsage.Scan.
+type ScanMsg struct {
+	// Files and Size count the files scanned so far.
+	Files int
+	Size  int64
+	// Done is set for the last message of the scan.
+	Done bool
+
+	du    *DiskUsage
+	id    int
+	ch    <-chan ScanMsg
+	ctx   context.Context
+	sizes map[string]Usage
+}
+
+// DiskUsage shows the disk usage of the files in a tree of Nodes, the way ncdu does.
+// It is a tree.Column rendering the size of every node, and a bar with its percentage of
+// the size of its parent directory. It can also sort the directories by size.
+//
+// The sizes of the directories which are not loaded come from a scan of the file system,
+// running in the background. The sizes of the loaded directories are aggregated from
+// their children, and are updated when their children change, so DiskUsage must
+// receive the messages of the Bubble Tea program, like a nested model.
+type DiskUsage struct {
+	// BarWidth is the width of the percentage bar, which is not rendered when zero.
+	BarWidth int
+
+	root     *Node
+	usage    *tree.Aggregate[Usage]
+	sizes    map[string]Usage
+	sorted   bool
+	scanning bool
+	scanID   int
+	cancel   context.CancelFunc
+}
+
+// NewDiskUsage returns a DiskUsage for the tree of root. The sizes of the directories
+// which are not loaded are unknown until the Scan finishes.
+func NewDiskUsage(root *Node) *DiskUsage {
+	d := &DiskUsage{BarWidth: DefaultBarWidth, root: root}
+	d.usage = tree.NewAggregate(d.leaf, d.reduce)
+	return d
+}
+
+func (d *DiskUsage) leaf(n tree.Node) Usage {
+	node, ok := n.(*Node)
+	if !ok || node.loop {
+		return Usage{}
+	}
+	if node.IsDir() {
+		return d.sizes[node.dirPath()]
+	}
+	if node.info == nil {
+		return Usage{}
+	}
+	return Usage{Size: node.info.Size(), Files: 1}
+}
+
+func (d *DiskUsage) reduce(_ tree.Node, children []Usage) Usage {
+	u := Usage{}
+	for _, c := range children {
+		u = u.add(c)
+	}
+	return u
+}
+
+// Usage returns the disk usage of node n.
+func (d *DiskUsage) Usage(n *Node) Usage {
+	return d.usage.Value(n)
+}
+
+// known checks if the usage of node n is known, which is not the case for the directories
+// which are not loaded, before the scan is done.
+func (d *DiskUsage) known(n *Node) bool {
+	if !n.IsDir() || n.loop || len(n.children) > 0 {
+		return true
+	}
+	_, ok := d.sizes[n.dirPath()]
+	return ok
+}
+
+// Scanning reports whether the scan of the file system is running.
+func (d *DiskUsage) Scanning() bool {
+	return d.scanning
+}
+
+// Sorted reports whether the directories are sorted by size.
+func (d *DiskUsage) Sorted() bool {
+	return d.sorted
+}
+
+// SortBySize sorts the children of the directories by their size, from the largest,
+// or by name when on is false. The returned command keeps the tree Model cursor on
+// the selected node.
+func (d *DiskUsage) SortBySize(on bool) tea.Cmd {
+	d.sorted = on
+	if on {
+		d.root.SortBy(d.compare)
+	} else {
+		d.root.SortBy(nil)
+	}
+	return changed
+}
+
+func (d *DiskUsage) compare(a, b *Node) int {
+	if c := cmp.Compare(d.Usage(b).Size, d.Usage(a).Size); c != 0 {
+		return c
+	}
+	return byName(a, b)
+}
+
+func changed() tea.Msg {
+	return tree.ChangedMsg{}
+}
+
+// Scan starts scanning the file system in the background, canceling the previous scan.
+// The command emits ScanMsg messages with the progress of the scan.
+func (d *DiskUsage) Scan() tea.Cmd {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.scanning = true
+	d.scanID++
+
+	ch := make(chan ScanMsg, 1)
+	s := scanner{
+		ctx:   ctx,
+		fsys:  d.root.fsys,
+		cfg:   *d.root.cfg,
+		sizes: make(map[string]Usage),
+		ch:    ch,
+		msg:   ScanMsg{du: d, id: d.scanID, ch: ch, ctx: ctx},
+	}
+	go s.run(d.root.dirPath(), d.root.rel, d.root.rules)
+	return wait(ctx, ch)
+}
+
+func wait(ctx context.Context, ch <-chan ScanMsg) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-ch:
+			return msg
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Update handles the progress of the scan, and updates the sizes of the directories
+// whose children changed.
+func (d *DiskUsage) Update(msg tea.Msg) tea.Cmd {
+	switch m := msg.(type) {
+	case ScanMsg:
+		if m.du != d || m.id != d.scanID {
+			return nil
+		}
+		if !m.Done {
+			return wait(m.ctx, m.ch)
+		}
+		d.scanning = false
+		d.cancel()
+		d.sizes = m.sizes
+		d.usage.Reset()
+		if d.sorted {
+			return d.SortBySize(true)
+		}
+		return changed
+	case tree.ExpandedMsg, tree.ChangedMsg:
+		d.usage.Update(msg)
+	}
+	return nil
+}
+
+// Render renders the size of node n, its percentage of the size of its parent directory
+// and the percentage bar.
+func (d *DiskUsage) Render(n tree.Node) string {
+	node, ok := n.(*Node)
+	if !ok {
+		return ""
+	}
+	if !d.known(node) {
+		if d.scanning {
+			return fmt.Sprintf("%10s", tree.Ellipsis)
+		}
+		return ""
+	}
+	u := d.Usage(node)
+	pct := 1.0
+	if node.parent != nil {
+		pct = 0
+		if total := d.Usage(node.parent).Size; total > 0 {
+			pct = float64(u.Size) / float64(total)
+		}
+	}
+	s := fmt.Sprintf("%10s %5.1f%%", size.Format(u.Size), pct*100)
+	if d.BarWidth > 0 {
+		filled := int(pct*float64(d.BarWidth) + 0.5)
+		s += " [" + strings.Repeat("#", filled) + strings.Repeat(" ", d.BarWidth-filled) + "]"
+	}
+	return s
+}
+
+// FormatSize formats a size in bytes using binary units, like "1.5 KiB".
+func FormatSize(b int64) string {
+	return size.Format(b)
+}
+
+// scanner computes the disk usage of the directories of a file system, in a goroutine.
+type scanner struct {
+	ctx   context.Context
+	fsys  fs.FS
+	cfg   config
+	sizes map[string]Usage
+	ch    chan<- ScanMsg
+	msg   ScanMsg
+	last  time.Time
+}
+
+func (s *scanner) run(dir, rel string, rules []rule) {
+	s.dir(dir, rel, rules)
+	msg := s.msg
+	msg.Done = true
+	msg.sizes = s.sizes
+	select {
+	case s.ch <- msg:
+	case <-s.ctx.Done():
+	}
+}
+
+// dir computes the disk usage of the directory, using the same exclusion rules as the Nodes.
+func (s *scanner) dir(dir, rel string, rules []rule) Usage {
+	u := Usage{}
+	if s.ctx.Err() != nil {
+		return u
+	}
+	l := readDir(s.fsys, &s.cfg, dir)
+	if len(l.gitIgnore) > 0 {
+		rules = append(slices.Clip(rules), parseRules(rel, strings.Split(string(l.gitIgnore), "\n")...)...)
+	}
+	for _, e := range l.entries {
+		crel := path.Join(rel, e.Name())
+		if ignored(rules, crel, e.IsDir()) {
+			continue
+		}
+		if e.IsDir() {
+			u = u.add(s.dir(path.Join(dir, e.Name()), crel, rules))
+			continue
+		}
+		if e.info != nil {
+			u = u.add(Usage{Size: e.info.Size(), Files: 1})
+			s.msg.Files++
+			s.msg.Size += e.info.Size()
+			s.progress()
+		}
+	}
+	s.sizes[dir] = u
+	return u
+}
+
+// progress sends a progress update, unless one was sent recently or wasn't received yet.
+func (s *scanner) progress() {
+	if time.Since(s.last) < scanProgressInterval {
+		return
+	}
+	s.last = time.Now()
+	select {
+	case s.ch <- s.msg:
+	default:
+	}
+}
@@ -0,0 +1,174 @@
+package fstree
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+func duFS() fstest.MapFS {
+	return fstest.MapFS{
+		"a.txt":       {Data: make([]byte, 100)},
+		"big/x.bin":   {Data: make([]byte, 2000)},
+		"big/y.bin":   {Data: make([]byte, 1000)},
+		"small/z.txt": {Data: make([]byte, 50)},
+		"small/.h":    {Data: make([]byte, 5000)},
+	}
+}
+
+// scan runs the scan of d to completion, returning the number of ScanMsg received.
+func scan(t *testing.T, d *DiskUsage) int {
+	t.Helper()
+	count := 0
+	cmd := d.Scan()
+	if !d.Scanning() {
+		t.Fatalf("Scanning() = false after Scan()")
+	}
+	for cmd != nil {
+		msg, ok := cmd().(ScanMsg)
+		if !ok {
+			break
+		}
+		count++
+		cmd = d.Update(msg)
+		if msg.Done {
+			break
+		}
+	}
+	if d.Scanning() {
+		t.Fatalf("Scanning() = true after the last ScanMsg")
+	}
+	return count
+}
+
+func TestDiskUsage(t *testing.T) {
+	root, err := New(duFS(), ".")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	d := NewDiskUsage(root)
+	big := child(root, "big")
+	if got := d.Render(big); got != "" {
+		t.Errorf("Render() before the scan = %q, want empty", got)
+	}
+
+	if n := scan(t, d); n == 0 {
+		t.Fatalf("the scan sent no messages")
+	}
+	tests := []struct {
+		name string
+		want Usage
+	}{
+		{name: "a.txt", want: Usage{Size: 100, Files: 1}},
+		{name: "big", want: Usage{Size: 3000, Files: 2}},
+		// hidden files are excluded, like in the tree
+		{name: "small", want: Usage{Size: 50, Files: 1}},
+	}
+	for _, tt := range tests {
+		if got := d.Usage(child(root, tt.name)); got != tt.want {
+			t.Errorf("Usage(%s) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if got, want := d.Usage(root), (Usage{Size: 3150, Files: 4}); got != want {
+		t.Errorf("Usage(root) = %+v, want %+v", got, want)
+	}
+
+	want := "   2.9 KiB  95.2% [##########]"
+	if got := d.Render(big); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	// loading the directory aggregates the sizes of its children
+	expand(big)
+	d.Update(tree.ExpandedMsg{Node: big})
+	if got := d.Usage(big); got != (Usage{Size: 3000, Files: 2}) {
+		t.Errorf("Usage(big) after loading = %+v", got)
+	}
+}
+
+func TestDiskUsage_SortBySize(t *testing.T) {
+	root, err := New(duFS(), ".")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	d := NewDiskUsage(root)
+	scan(t, d)
+
+	cmd := d.SortBySize(true)
+	if _, ok := cmd().(tree.ChangedMsg); !ok {
+		t.Errorf("SortBySize() command does not emit a tree.ChangedMsg")
+	}
+	if diff := cmp.Diff([]string{"big", "a.txt", "small"}, childNames(root)); diff != "" {
+		t.Errorf("sorted by size mismatch (-want +got):\n%s", diff)
+	}
+	// directories loaded later are sorted too
+	big := child(root, "big")
+	expand(big)
+	if diff := cmp.Diff([]string{"x.bin", "y.bin"}, childNames(big)); diff != "" {
+		t.Errorf("loaded directory order mismatch (-want +got):\n%s", diff)
+	}
+
+	d.SortBySize(false)
+	if diff := cmp.Diff([]string{"a.txt", "big", "small"}, childNames(root)); diff != "" {
+		t.Errorf("sorted by name mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiskUsage_Scan_restart(t *testing.T) {
+	root, err := New(duFS(), ".")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	d := NewDiskUsage(root)
+	first := d.Scan()
+	second := d.Scan()
+
+	// the first scan is canceled, and its messages are ignored
+	if msg := first(); msg != nil {
+		if cmd := d.Update(msg); cmd != nil {
+			t.Errorf("Update() with a message of a canceled scan returned a command")
+		}
+	}
+	for cmd := second; cmd != nil; {
+		msg := cmd()
+		cmd = d.Update(msg)
+		if m, ok := msg.(ScanMsg); !ok || m.Done {
+			break
+		}
+	}
+	if d.Scanning() {
+		t.Errorf("Scanning() = true after the second scan finished")
+	}
+}
+
+func TestDiskUsage_column(t *testing.T) {
+	root, err := New(duFS(), ".", WithName("root"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	d := NewDiskUsage(root)
+	d.BarWidth = 0
+	scan(t, d)
+
+	rendered := tree.New(tree.Nodes{root}).RenderAll(tree.WithColumns(d))
+	want := []string{
+		"   3.1 KiB 100.0% └─ root",
+		"     100 B   3.2%    ├─ a.txt",
+		"   2.9 KiB  95.2%    ├─ big",
+		"      50 B   1.6%    └─ small",
+	}
+	if diff := cmp.Diff(want, strings.Split(rendered, "\n")); diff != "" {
+		t.Errorf("RenderAll() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func childNames(n *Node) []string {
+	result := make([]string, 0)
+	for _, c := range n.children {
+		result = append(result, c.name)
+	}
+	return result
+}
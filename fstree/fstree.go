@@ -0,0 +1,461 @@
+// Package fstree provides tree nodes for browsing the files and directories of an fs.FS.
+//
+// The directories are read lazily, the first time they are expanded in the tree.
+package fstree
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// maxLinks is the maximum number of symbolic links followed when resolving a path.
+const maxLinks = 40
+
+type config struct {
+	name           string
+	showHidden     bool
+	gitIgnore      bool
+	followSymlinks bool
+	rules          []rule
+	// compare sets the order of the children of the directories, which is by name when nil.
+	compare func(a, b *Node) int
+}
+
+// Option configures how the nodes are built from the file system.
+type Option func(*config)
+
+// WithName sets the name shown for the root node, which is its path by default.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// ShowHidden includes the files and directories whose names start with a dot.
+func ShowHidden() Option {
+	return func(c *config) {
+		c.showHidden = true
+	}
+}
+
+// Ignore excludes the files matching the gitignore style patterns, relative to the root node.
+func Ignore(patterns ...string) Option {
+	return func(c *config) {
+		c.rules = append(c.rules, parseRules("", patterns...)...)
+	}
+}
+
+// GitIgnore excludes the files matching the patterns in the .gitignore files
+// of the directories being read.
+func GitIgnore() Option {
+	return func(c *config) {
+		c.gitIgnore = true
+	}
+}
+
+// FollowSymlinks shows the symbolic links to directories as directories, which can be expanded.
+// The links pointing back to one of their ancestors are detected, and are not followed.
+func FollowSymlinks() Option {
+	return func(c *config) {
+		c.followSymlinks = true
+	}
+}
+
+// Node is a tree.Node for a file or directory of an fs.FS.
+type Node struct {
+	fsys fs.FS
+	cfg  *config
+
+	parent *Node
+	name   string
+	// path is the name of the node in fsys.
+	path string
+	// rel is the path of the node relative to the root node.
+	rel string
+	// real is the path of the node in fsys with the symbolic links resolved,
+	// or empty if it could not be resolved.
+	real string
+	info fs.FileInfo
+	link string
+	// symlink is set when the node is a symbolic link.
+	symlink bool
+	loop    bool
+	err     error
+
+	state    tree.NodeState
+	loaded   bool
+	rules    []rule
+	children []*Node
+}
+
+// New builds the node for the root directory, or file, of fsys.
+// The contents of the root directory are read immediately, the ones of its descendants
+// the first time they are expanded.
+func New(fsys fs.FS, root string, opts ...Option) (*Node, error) {
+	cfg := config{name: root}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !fs.ValidPath(root) {
+		return nil, &fs.PathError{Op: "open", Path: root, Err: fs.ErrInvalid}
+	}
+	info, err := fs.Stat(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{
+		fsys:  fsys,
+		cfg:   &cfg,
+		name:  cfg.name,
+		path:  root,
+		real:  root,
+		info:  info,
+		rules: cfg.rules,
+	}
+	if n.IsDir() {
+		n.state = tree.NodeCollapsible
+		if err := n.Load(); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// Dir builds the node for the directory dir of the operating system's file system.
+func Dir(dir string, opts ...Option) (*Node, error) {
+	return New(os.DirFS(dir), ".", append([]Option{WithName(dir)}, opts...)...)
+}
+
+// Load reads the entries of the directory, replacing the children of the node.
+// The children which are still present in the directory are kept, together with their state.
+func (n *Node) Load() error {
+	if !n.IsDir() || n.loop {
+		return nil
+	}
+	l := readDir(n.fsys, n.cfg, n.dirPath())
+	n.apply(l)
+	return l.err
+}
+
+// listing holds the contents of a directory, as read from the file system.
+type listing struct {
+	entries []entry
+	// gitIgnore holds the contents of the .gitignore file of the directory.
+	gitIgnore []byte
+	err       error
+}
+
+// entry is a fs.DirEntry together with its fs.FileInfo.
+type entry struct {
+	fs.DirEntry
+	info fs.FileInfo
+	err  error
+}
+
+func (e entry) Info() (fs.FileInfo, error) {
+	return e.info, e.err
+}
+
+// readDir reads the entries of the directory dir of fsys. It only accesses the
+// file system, so it's safe to call it outside the goroutine updating the nodes.
+func readDir(fsys fs.FS, cfg *config, dir string) listing {
+	l := listing{}
+	entries, err := fs.ReadDir(fsys, dir)
+	if l.err = err; err != nil {
+		return l
+	}
+	l.entries = make([]entry, 0, len(entries))
+	for _, e := range entries {
+		if !cfg.showHidden && isHidden(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		l.entries = append(l.entries, entry{DirEntry: e, info: info, err: err})
+	}
+	if cfg.gitIgnore {
+		l.gitIgnore, _ = fs.ReadFile(fsys, path.Join(dir, ".gitignore"))
+	}
+	return l
+}
+
+// changes holds the children of a directory which changed between two listings.
+type changes struct {
+	added, removed, modified []*Node
+}
+
+// apply replaces the children of the node with the entries of the listing, keeping the nodes
+// of the entries which were already present.
+func (n *Node) apply(l listing) changes {
+	n.loaded = true
+	if n.err = l.err; l.err != nil {
+		return changes{}
+	}
+
+	rules := n.rules
+	if len(l.gitIgnore) > 0 {
+		rules = append(slices.Clip(rules), parseRules(n.rel, strings.Split(string(l.gitIgnore), "\n")...)...)
+	}
+
+	existing := make(map[string]*Node, len(n.children))
+	for _, c := range n.children {
+		existing[c.name] = c
+	}
+	ch := changes{}
+	children := make([]*Node, 0, len(l.entries))
+	for _, e := range l.entries {
+		c := n.child(e, rules)
+		if ignored(rules, c.rel, c.IsDir()) {
+			continue
+		}
+		old, ok := existing[c.name]
+		switch {
+		case !ok:
+			ch.added = append(ch.added, c)
+		case old.IsDir() != c.IsDir():
+			ch.removed = append(ch.removed, old)
+			ch.added = append(ch.added, c)
+		default:
+			if !sameInfo(old.info, c.info) {
+				ch.modified = append(ch.modified, old)
+			}
+			old.info, old.link, old.err, old.rules = c.info, c.link, c.err, c.rules
+			c = old
+		}
+		delete(existing, c.name)
+		children = append(children, c)
+	}
+	for _, c := range n.children {
+		if _, ok := existing[c.name]; ok {
+			ch.removed = append(ch.removed, c)
+		}
+	}
+	if n.cfg.compare != nil {
+		slices.SortStableFunc(children, n.cfg.compare)
+	}
+	n.children = children
+	return ch
+}
+
+// SortBy sets the order of the children of all the directories in the tree of n,
+// which is by name when cmp is nil. The directories already loaded are sorted again.
+func (n *Node) SortBy(cmp func(a, b *Node) int) {
+	n.cfg.compare = cmp
+	if cmp == nil {
+		cmp = byName
+	}
+	root := n
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.sort(cmp)
+}
+
+func (n *Node) sort(cmp func(a, b *Node) int) {
+	slices.SortStableFunc(n.children, cmp)
+	for _, c := range n.children {
+		c.sort(cmp)
+	}
+}
+
+func byName(a, b *Node) int {
+	return strings.Compare(a.name, b.name)
+}
+
+// sameInfo checks if the two fs.FileInfo describe the same version of a file.
+func sameInfo(a, b fs.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Size() == b.Size() && a.Mode() == b.Mode() && a.ModTime().Equal(b.ModTime())
+}
+
+// child builds the node for the directory entry e of n.
+func (n *Node) child(e fs.DirEntry, rules []rule) *Node {
+	c := &Node{
+		fsys:   n.fsys,
+		cfg:    n.cfg,
+		parent: n,
+		name:   e.Name(),
+		path:   path.Join(n.path, e.Name()),
+		rel:    path.Join(n.rel, e.Name()),
+		rules:  rules,
+	}
+	if n.real != "" {
+		c.real = path.Join(n.real, e.Name())
+	}
+	c.info, c.err = e.Info()
+	if e.Type()&fs.ModeSymlink != 0 {
+		c.symlink = true
+		c.resolveLink()
+	}
+	if c.IsDir() && !c.loop {
+		c.state = tree.NodeCollapsible | tree.NodeCollapsed
+	}
+	return c
+}
+
+// resolveLink reads the target of a symbolic link and, if the links are followed,
+// replaces the node's FileInfo with the target's.
+func (n *Node) resolveLink() {
+	n.link, _ = fs.ReadLink(n.fsys, n.path)
+	if !n.cfg.followSymlinks {
+		return
+	}
+	info, err := fs.Stat(n.fsys, n.path)
+	if err != nil {
+		// A broken link is shown as a file, with the information about the link itself.
+		n.err = err
+		return
+	}
+	n.info = info
+	n.real = n.realPath()
+	n.loop = n.isLoop()
+}
+
+// realPath resolves the symbolic links in the path of the node. It returns an empty
+// string when the target can't be resolved inside the file system.
+func (n *Node) realPath() string {
+	if n.parent == nil || n.parent.real == "" {
+		return ""
+	}
+	p := path.Join(n.parent.real, n.name)
+	for i := 0; i < maxLinks; i++ {
+		target, err := fs.ReadLink(n.fsys, p)
+		if err != nil {
+			if i == 0 {
+				return ""
+			}
+			return p
+		}
+		if path.IsAbs(target) {
+			return ""
+		}
+		if p = path.Join(path.Dir(p), target); !fs.ValidPath(p) {
+			return ""
+		}
+	}
+	return ""
+}
+
+// isLoop checks if the node is a link to one of its ancestors.
+func (n *Node) isLoop() bool {
+	for a := n.parent; a != nil; a = a.parent {
+		if n.real != "" && a.real == n.real {
+			return true
+		}
+		if a.info != nil && os.SameFile(a.info, n.info) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirPath returns the path used for reading the contents of the node.
+func (n *Node) dirPath() string {
+	if n.real != "" {
+		return n.real
+	}
+	return n.path
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
+// Name returns the name of the file.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Title returns the name of the file, to be used by the tree.Breadcrumb and tree.FileIcons.
+func (n *Node) Title() string {
+	return n.name
+}
+
+// Path returns the name of the file in the fs.FS the node was built from.
+func (n *Node) Path() string {
+	return n.path
+}
+
+// Info returns the fs.FileInfo of the file. For the symbolic links which are followed
+// it describes the target of the link.
+func (n *Node) Info() fs.FileInfo {
+	return n.info
+}
+
+// IsDir reports whether the node is a directory, or a followed link to a directory.
+func (n *Node) IsDir() bool {
+	return n.info != nil && n.info.IsDir()
+}
+
+// IsSymlink reports whether the file is a symbolic link.
+func (n *Node) IsSymlink() bool {
+	return n.symlink
+}
+
+// Link returns the target of the symbolic link, if the node is one.
+func (n *Node) Link() string {
+	return n.link
+}
+
+// IsLoop reports whether the node is a symbolic link to one of its ancestors.
+// Such links are not expanded.
+func (n *Node) IsLoop() bool {
+	return n.loop
+}
+
+// Err returns the error encountered when reading the file or its directory entries.
+func (n *Node) Err() error {
+	return n.err
+}
+
+func (n *Node) Parent() tree.Node {
+	if n == nil || n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Init() tea.Cmd {
+	return nil
+}
+
+func (n *Node) View() tea.View {
+	if n.link != "" {
+		return tea.NewView(n.name + " → " + n.link)
+	}
+	return tea.NewView(n.name)
+}
+
+func (n *Node) Children() tree.Nodes {
+	if len(n.children) == 0 {
+		return nil
+	}
+	nodes := make(tree.Nodes, len(n.children))
+	for i, c := range n.children {
+		nodes[i] = c
+	}
+	return nodes
+}
+
+func (n *Node) State() tree.NodeState {
+	return n.state
+}
+
+// Update sets the state of the node, and reads the directory entries when it is expanded for the first time.
+func (n *Node) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tree.NodeState:
+		n.state = m
+		if !m.Is(tree.NodeCollapsed) && n.IsDir() && !n.loaded {
+			_ = n.Load()
+		}
+	}
+	return n, nil
+}
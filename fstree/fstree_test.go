@@ -0,0 +1,248 @@
+package fstree
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md":            {Data: []byte("# readme")},
+		".hidden":              {Data: []byte("secret")},
+		".gitignore":           {Data: []byte("*.log\nbuild/\n")},
+		"app.log":              {Data: []byte("log")},
+		"build/out":            {Data: []byte("binary")},
+		"src/main.go":          {Data: []byte("package main")},
+		"src/util/util.go":     {Data: []byte("package util")},
+		"src/util/.gitignore":  {Data: []byte("gen_*.go\n!gen_keep.go\n")},
+		"src/util/gen_a.go":    {Data: []byte("package util")},
+		"src/util/gen_keep.go": {Data: []byte("package util")},
+		"link-to-src":          {Data: []byte("src"), Mode: fs.ModeSymlink},
+		"src/loop":             {Data: []byte(".."), Mode: fs.ModeSymlink},
+		"broken":               {Data: []byte("missing"), Mode: fs.ModeSymlink},
+	}
+}
+
+// names returns the names of the nodes, with the children of the expanded ones indented.
+func names(nodes tree.Nodes) []string {
+	result := make([]string, 0)
+	for _, n := range nodes {
+		result = append(result, n.(*Node).Name())
+		if !n.State().Is(tree.NodeCollapsed) {
+			for _, c := range names(n.Children()) {
+				result = append(result, "  "+c)
+			}
+		}
+	}
+	return result
+}
+
+func expand(n tree.Node) {
+	n.Update(n.State() &^ tree.NodeCollapsed)
+}
+
+func child(n tree.Node, name string) *Node {
+	for _, c := range n.Children() {
+		if c.(*Node).Name() == name {
+			return c.(*Node)
+		}
+	}
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "default",
+			want: []string{"root", "  README.md", "  app.log", "  broken", "  build", "  link-to-src", "  src"},
+		},
+		{
+			name: "hidden",
+			opts: []Option{ShowHidden()},
+			want: []string{"root", "  .gitignore", "  .hidden", "  README.md", "  app.log", "  broken", "  build", "  link-to-src", "  src"},
+		},
+		{
+			name: "ignore",
+			opts: []Option{Ignore("*.md", "/build", "!README.md")},
+			want: []string{"root", "  README.md", "  app.log", "  broken", "  link-to-src", "  src"},
+		},
+		{
+			name: "gitignore",
+			opts: []Option{GitIgnore()},
+			want: []string{"root", "  README.md", "  broken", "  link-to-src", "  src"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := New(testFS(), ".", append([]Option{WithName("root")}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, names(tree.Nodes{root})); diff != "" {
+				t.Errorf("New() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew_errors(t *testing.T) {
+	if _, err := New(testFS(), "/abs"); err == nil {
+		t.Errorf("New() with an invalid path expected error, got nil")
+	}
+	if _, err := New(testFS(), "missing"); err == nil {
+		t.Errorf("New() with a missing path expected error, got nil")
+	}
+	n, err := New(testFS(), "README.md")
+	if err != nil {
+		t.Fatalf("New() for a file error = %v", err)
+	}
+	if n.IsDir() || len(n.Children()) > 0 {
+		t.Errorf("New() for a file returned a directory node")
+	}
+}
+
+func TestNode_lazyLoading(t *testing.T) {
+	root, err := New(testFS(), ".", GitIgnore())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	src := child(root, "src")
+	if !src.State().Is(tree.NodeCollapsible | tree.NodeCollapsed) {
+		t.Errorf("directory state = %v, want collapsible and collapsed", src.State())
+	}
+	if src.loaded || len(src.Children()) != 0 {
+		t.Fatalf("directory was loaded before being expanded")
+	}
+
+	expand(src)
+	util := child(src, "util")
+	expand(util)
+	want := []string{"src", "  loop", "  main.go", "  util", "    gen_keep.go", "    util.go"}
+	if diff := cmp.Diff(want, names(tree.Nodes{src})); diff != "" {
+		t.Errorf("expanded directory mismatch (-want +got):\n%s", diff)
+	}
+	if got := child(util, "util.go").Info().Size(); got != int64(len("package util")) {
+		t.Errorf("Info().Size() = %d, want %d", got, len("package util"))
+	}
+	if got := child(util, "util.go").Path(); got != "src/util/util.go" {
+		t.Errorf("Path() = %q, want %q", got, "src/util/util.go")
+	}
+}
+
+func TestNode_symlinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantDir   bool
+		wantLoop  bool
+		wantNames []string
+	}{
+		{
+			name:      "not followed",
+			wantNames: []string{"link-to-src"},
+		},
+		{
+			name:      "followed",
+			opts:      []Option{FollowSymlinks()},
+			wantDir:   true,
+			wantLoop:  true,
+			wantNames: []string{"link-to-src", "  loop", "  main.go", "  util"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := New(testFS(), ".", tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			link := child(root, "link-to-src")
+			if !link.IsSymlink() || link.Link() != "src" {
+				t.Errorf("IsSymlink() = %t, Link() = %q, want a link to src", link.IsSymlink(), link.Link())
+			}
+			if got := link.View().Content; got != "link-to-src → src" {
+				t.Errorf("View() = %q, want the link target", got)
+			}
+			if link.IsDir() != tt.wantDir {
+				t.Fatalf("IsDir() = %t, want %t", link.IsDir(), tt.wantDir)
+			}
+			expand(link)
+			if diff := cmp.Diff(tt.wantNames, names(tree.Nodes{link})); diff != "" {
+				t.Errorf("link children mismatch (-want +got):\n%s", diff)
+			}
+			if !tt.wantDir {
+				return
+			}
+			loop := child(link, "loop")
+			if loop.IsLoop() != tt.wantLoop {
+				t.Errorf("IsLoop() = %t, want %t", loop.IsLoop(), tt.wantLoop)
+			}
+			if loop.State().Is(tree.NodeCollapsible) {
+				t.Errorf("a loop can be expanded")
+			}
+			broken := child(root, "broken")
+			if broken.IsDir() || broken.Err() == nil {
+				t.Errorf("broken link IsDir() = %t, Err() = %v, want a file with an error", broken.IsDir(), broken.Err())
+			}
+		})
+	}
+}
+
+func TestNode_Load_keepsState(t *testing.T) {
+	fsys := testFS()
+	root, err := New(fsys, ".")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	src := child(root, "src")
+	expand(src)
+	src.Update(src.State() | tree.NodeSelected)
+
+	delete(fsys, "README.md")
+	fsys["NEW.md"] = &fstest.MapFile{Data: []byte("new")}
+	if err := root.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if child(root, "src") != src {
+		t.Errorf("Load() replaced an existing node")
+	}
+	if !src.State().Is(tree.NodeSelected) || src.State().Is(tree.NodeCollapsed) {
+		t.Errorf("Load() lost the state of an existing node: %v", src.State())
+	}
+	if child(root, "README.md") != nil || child(root, "NEW.md") == nil {
+		t.Errorf("Load() did not update the directory entries: %v", names(root.Children()))
+	}
+}
+
+func TestDir_symlinkLoop(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// An absolute target can't be resolved inside the fs.FS, so the loop is detected with os.SameFile.
+	if err := os.Symlink(dir, filepath.Join(dir, "sub", "up")); err != nil {
+		t.Skipf("unable to create symlink: %v", err)
+	}
+	root, err := Dir(dir, FollowSymlinks())
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	sub := child(root, "sub")
+	expand(sub)
+	up := child(sub, "up")
+	if up == nil || !up.IsLoop() {
+		t.Fatalf("link to the root directory is not a loop: %v", up)
+	}
+	if root.Name() != dir {
+		t.Errorf("Name() = %q, want %q", root.Name(), dir)
+	}
+}
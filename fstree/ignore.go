@@ -0,0 +1,107 @@
+package fstree
+
+import (
+	"path"
+	"strings"
+)
+
+// rule is a single gitignore style pattern.
+type rule struct {
+	// base is the directory containing the file the rule was read from,
+	// relative to the root of the tree. The rule only applies to its descendants.
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// parseRules parses gitignore style patterns, one per line, which apply to the
+// descendants of the base directory.
+func parseRules(base string, lines ...string) []rule {
+	rules := make([]rule, 0, len(lines))
+	for _, l := range lines {
+		l = strings.TrimRight(strings.TrimSuffix(l, "\r"), " ")
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		r := rule{base: base}
+		if strings.HasPrefix(l, "!") {
+			r.negate = true
+			l = l[1:]
+		}
+		// A leading backslash escapes the '#' and '!' characters.
+		l = strings.TrimPrefix(l, `\`)
+		if strings.HasSuffix(l, "/") {
+			r.dirOnly = true
+			l = strings.TrimRight(l, "/")
+		}
+		if strings.Contains(l, "/") {
+			r.anchored = true
+			l = strings.TrimPrefix(l, "/")
+		}
+		if l == "" {
+			continue
+		}
+		r.pattern = l
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// match checks if the rule matches the file at rel, which is relative to the root of the tree.
+func (r rule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = rel[len(r.base)+1:]
+	}
+	if !r.anchored {
+		ok, _ := path.Match(r.pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+}
+
+// matchSegments matches a path against a pattern, both split in their components,
+// where a "**" component in the pattern matches any number of path components.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range name {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ignored checks if the file at rel is excluded by the rules. Like in gitignore
+// files, the last matching rule wins, so negated patterns can re-include files.
+func ignored(rules []rule, rel string, isDir bool) bool {
+	ignore := false
+	for _, r := range rules {
+		if r.match(rel, isDir) {
+			ignore = !r.negate
+		}
+	}
+	return ignore
+}
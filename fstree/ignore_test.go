@@ -0,0 +1,39 @@
+package fstree
+
+import "testing"
+
+func Test_ignored(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		patterns []string
+		rel      string
+		isDir    bool
+		want     bool
+	}{
+		{name: "basename", patterns: []string{"*.log"}, rel: "a/b/c.log", want: true},
+		{name: "no match", patterns: []string{"*.log"}, rel: "a/b/c.txt", want: false},
+		{name: "comment", patterns: []string{"#c.txt"}, rel: "#c.txt", want: false},
+		{name: "escaped comment", patterns: []string{`\#c.txt`}, rel: "#c.txt", want: true},
+		{name: "dir only on file", patterns: []string{"build/"}, rel: "build", want: false},
+		{name: "dir only on dir", patterns: []string{"build/"}, rel: "a/build", isDir: true, want: true},
+		{name: "anchored", patterns: []string{"/build"}, rel: "a/build", want: false},
+		{name: "anchored at root", patterns: []string{"/build"}, rel: "build", want: true},
+		{name: "middle slash", patterns: []string{"a/*.go"}, rel: "a/x.go", want: true},
+		{name: "middle slash is anchored", patterns: []string{"a/*.go"}, rel: "b/a/x.go", want: false},
+		{name: "double star prefix", patterns: []string{"**/x.go"}, rel: "a/b/x.go", want: true},
+		{name: "double star middle", patterns: []string{"a/**/x.go"}, rel: "a/x.go", want: true},
+		{name: "double star suffix", patterns: []string{"a/**"}, rel: "a/b/c", want: true},
+		{name: "negated", patterns: []string{"*.go", "!keep.go"}, rel: "keep.go", want: false},
+		{name: "negated then ignored", patterns: []string{"!keep.go", "*.go"}, rel: "keep.go", want: true},
+		{name: "base", base: "sub", patterns: []string{"/x"}, rel: "sub/x", want: true},
+		{name: "outside base", base: "sub", patterns: []string{"x"}, rel: "x", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ignored(parseRules(tt.base, tt.patterns...), tt.rel, tt.isDir); got != tt.want {
+				t.Errorf("ignored() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
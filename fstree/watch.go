@@ -0,0 +1,144 @@
+package fstree
+
+import (
+	"io/fs"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// DefaultInterval is the time between two reads of the watched directories.
+const DefaultInterval = 2 * time.Second
+
+// Watcher polls the expanded directories of a tree of Nodes, and updates the nodes in place
+// when the contents of the directories change. Every batch of changes is sent to the tree
+// Model as a tree.ChangedMsg, which keeps the cursor on the selected node.
+//
+// The file system is read in the background, but the nodes are only modified in Update,
+// so the Watcher must receive the messages of the Bubble Tea program, like a nested model.
+type Watcher struct {
+	// Interval is the time between two reads of the watched directories.
+	Interval time.Duration
+
+	root    *Node
+	stopped bool
+}
+
+// pollMsg asks the Watcher to read the watched directories.
+type pollMsg struct {
+	w *Watcher
+}
+
+// polledMsg holds the contents of the watched directories, read in the background.
+type polledMsg struct {
+	w    *Watcher
+	dirs []polled
+}
+
+type polled struct {
+	node *Node
+	fsys fs.FS
+	cfg  *config
+	dir  string
+	listing
+}
+
+// NewWatcher returns a Watcher for the tree starting at root.
+func NewWatcher(root *Node, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Watcher{Interval: interval, root: root}
+}
+
+// Init starts the polling.
+func (w *Watcher) Init() tea.Cmd {
+	w.stopped = false
+	return w.tick()
+}
+
+// Stop stops the polling. It can be restarted with Init.
+func (w *Watcher) Stop() {
+	w.stopped = true
+}
+
+func (w *Watcher) tick() tea.Cmd {
+	return tea.Tick(w.Interval, func(time.Time) tea.Msg {
+		return pollMsg{w: w}
+	})
+}
+
+// Update handles the polling messages of the Watcher, and ignores everything else.
+func (w *Watcher) Update(msg tea.Msg) tea.Cmd {
+	switch m := msg.(type) {
+	case pollMsg:
+		if m.w != w || w.stopped {
+			return nil
+		}
+		return w.poll(w.watched())
+	case polledMsg:
+		if m.w != w || w.stopped {
+			return nil
+		}
+		return tea.Batch(w.apply(m.dirs), w.tick())
+	}
+	return nil
+}
+
+// watched returns the directories to read, which are the loaded directories visible in the tree.
+func (w *Watcher) watched() []polled {
+	dirs := make([]polled, 0)
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !n.loaded || n.loop || n.State().Is(tree.NodeCollapsed) {
+			return
+		}
+		dirs = append(dirs, polled{node: n, fsys: n.fsys, cfg: n.cfg, dir: n.dirPath()})
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(w.root)
+	return dirs
+}
+
+// poll reads the directories in the background.
+func (w *Watcher) poll(dirs []polled) tea.Cmd {
+	return func() tea.Msg {
+		for i := range dirs {
+			dirs[i].listing = readDir(dirs[i].fsys, dirs[i].cfg, dirs[i].dir)
+		}
+		return polledMsg{w: w, dirs: dirs}
+	}
+}
+
+// apply updates the nodes with the contents of the directories, returning a command
+// emitting the tree.ChangedMsg if anything changed.
+func (w *Watcher) apply(dirs []polled) tea.Cmd {
+	msg := tree.ChangedMsg{}
+	for _, d := range dirs {
+		if d.err != nil {
+			// The directory was removed, which is reported by its parent.
+			continue
+		}
+		ch := d.node.apply(d.listing)
+		msg.Added = append(msg.Added, nodes(ch.added)...)
+		msg.Removed = append(msg.Removed, nodes(ch.removed)...)
+		msg.Modified = append(msg.Modified, nodes(ch.modified)...)
+	}
+	if len(msg.Added)+len(msg.Removed)+len(msg.Modified) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+func nodes(nn []*Node) tree.Nodes {
+	result := make(tree.Nodes, len(nn))
+	for i, n := range nn {
+		result[i] = n
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package fstree
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// poll runs a polling cycle of the watcher, returning the tree.ChangedMsg it emitted, if any.
+func poll(t *testing.T, w *Watcher) *tree.ChangedMsg {
+	t.Helper()
+	cmd := w.Update(pollMsg{w: w})
+	if cmd == nil {
+		t.Fatalf("Update(pollMsg) returned no command")
+	}
+	polled, ok := cmd().(polledMsg)
+	if !ok {
+		t.Fatalf("poll command returned %T, want polledMsg", polled)
+	}
+	var changed *tree.ChangedMsg
+	var run func(tea.Cmd)
+	run = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		switch msg := cmd().(type) {
+		case tea.BatchMsg:
+			for _, c := range msg {
+				run(c)
+			}
+		case tree.ChangedMsg:
+			changed = &msg
+		}
+	}
+	run(w.Update(polled))
+	return changed
+}
+
+func nodeNames(nodes tree.Nodes) []string {
+	result := make([]string, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.(*Node).Path()
+	}
+	return result
+}
+
+func TestWatcher(t *testing.T) {
+	fsys := testFS()
+	root, err := New(fsys, ".")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	src := child(root, "src")
+	expand(src)
+	build := child(root, "build")
+
+	w := NewWatcher(root, time.Millisecond)
+	if got := poll(t, w); got != nil {
+		t.Fatalf("poll() without changes = %v, want nil", got)
+	}
+
+	fsys["src/new.go"] = &fstest.MapFile{Data: []byte("package main")}
+	fsys["src/main.go"] = &fstest.MapFile{Data: []byte("package main\n\nfunc main() {}")}
+	delete(fsys, "README.md")
+	// The collapsed directories are not watched.
+	fsys["build/other"] = &fstest.MapFile{Data: []byte("binary")}
+
+	got := poll(t, w)
+	if got == nil {
+		t.Fatalf("poll() returned no changes")
+	}
+	if diff := cmp.Diff([]string{"src/new.go"}, nodeNames(got.Added)); diff != "" {
+		t.Errorf("Added mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"README.md"}, nodeNames(got.Removed)); diff != "" {
+		t.Errorf("Removed mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"src/main.go"}, nodeNames(got.Modified)); diff != "" {
+		t.Errorf("Modified mismatch (-want +got):\n%s", diff)
+	}
+	if child(root, "src") != src || src.State().Is(tree.NodeCollapsed) {
+		t.Errorf("the expanded directory lost its state")
+	}
+	if len(build.children) != 0 {
+		t.Errorf("the collapsed directory was read")
+	}
+}
+
+func TestWatcher_Stop(t *testing.T) {
+	root, err := New(testFS(), ".")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	w := NewWatcher(root, 0)
+	if w.Interval != DefaultInterval {
+		t.Errorf("Interval = %v, want %v", w.Interval, DefaultInterval)
+	}
+	w.Stop()
+	if cmd := w.Update(pollMsg{w: w}); cmd != nil {
+		t.Errorf("Update() on a stopped watcher returned a command")
+	}
+	if cmd := w.Update(pollMsg{w: NewWatcher(root, 0)}); cmd != nil {
+		t.Errorf("Update() with the message of another watcher returned a command")
+	}
+}
+
+func TestWatcher_keepsCursor(t *testing.T) {
+	fsys := testFS()
+	root, err := New(fsys, ".", WithName("root"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	m := tree.New(tree.Nodes{root})
+	m.SetWidth(40)
+	m.SetHeight(10)
+	m.Init()
+	m.MoveDown(3)
+	selected := m.CurrentNode()
+	if got := selected.(*Node).Name(); got != "broken" {
+		t.Fatalf("CurrentNode() = %q, want broken", got)
+	}
+
+	fsys["0-first"] = &fstest.MapFile{Data: []byte("new")}
+	w := NewWatcher(root, time.Millisecond)
+	changed := poll(t, w)
+	if changed == nil {
+		t.Fatalf("poll() returned no changes")
+	}
+	m.Update(*changed)
+	if got := m.CurrentNode(); got != selected {
+		t.Errorf("CurrentNode() after the changes = %v, want %v", got, selected)
+	}
+}
@@ -10,6 +10,7 @@ require (
 	github.com/charmbracelet/x/ansi v0.11.7
 	github.com/google/go-cmp v0.7.0
 	github.com/muesli/reflow v0.3.0
+	golang.org/x/net v0.52.0
 )
 
 require (
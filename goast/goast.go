@@ -0,0 +1,358 @@
+// Package goast provides tree nodes for the outline of a Go package, built with go/parser.
+//
+// The files of the package are the top level nodes, containing their declarations
+// in source order: types, functions, constants and variables. The methods are grouped
+// under the nodes of their receiver types.
+//
+// Every node knows its position in the source code, and selecting it in the tree
+// emits a SelectedMsg, which can be used for opening the file at that line:
+//
+//	case goast.SelectedMsg:
+//		return m, openEditor(msg.Position.Filename, msg.Position.Line)
+package goast
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// Kind is the kind of declaration of a Node.
+type Kind int
+
+const (
+	Package Kind = iota
+	File
+	Type
+	Func
+	Method
+	Const
+	Var
+	// Receiver groups the methods of a type declared in another file of the package.
+	Receiver
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Package:
+		return "package"
+	case File:
+		return "file"
+	case Type:
+		return "type"
+	case Func, Method:
+		return "func"
+	case Const:
+		return "const"
+	case Var:
+		return "var"
+	case Receiver:
+		return "methods"
+	}
+	return ""
+}
+
+// SelectedMsg is emitted when a Node gets selected in the tree.
+type SelectedMsg struct {
+	tree.Node
+	Position token.Position
+}
+
+// String returns the location of the selected node as file:line.
+func (m SelectedMsg) String() string {
+	return fmt.Sprintf("%s:%d", m.Position.Filename, m.Position.Line)
+}
+
+// Styles contains the style definitions for the nodes.
+type Styles struct {
+	Keyword lipgloss.Style
+	Name    lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for the nodes.
+func DefaultStyles() Styles {
+	return Styles{
+		Keyword: lipgloss.NewStyle().Faint(true),
+		Name:    lipgloss.NewStyle(),
+	}
+}
+
+type config struct {
+	styles       Styles
+	tests        bool
+	exportedOnly bool
+}
+
+// Option configures how the nodes are built from the source code.
+type Option func(*config)
+
+// WithStyles sets the Styles used for rendering the nodes.
+func WithStyles(s Styles) Option {
+	return func(c *config) {
+		c.styles = s
+	}
+}
+
+// IncludeTests includes the _test.go files of the directory.
+func IncludeTests() Option {
+	return func(c *config) {
+		c.tests = true
+	}
+}
+
+// ExportedOnly leaves out the unexported declarations.
+func ExportedOnly() Option {
+	return func(c *config) {
+		c.exportedOnly = true
+	}
+}
+
+// Node is a tree.Node for a Go package, file or declaration.
+type Node struct {
+	cfg *config
+
+	parent   *Node
+	kind     Kind
+	name     string
+	pos      token.Position
+	children []*Node
+	state    tree.NodeState
+}
+
+// ParseDir parses the Go files of the directory dir and returns the node for their package.
+func ParseDir(dir string, opts ...Option) (*Node, error) {
+	cfg := config{styles: DefaultStyles()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if !cfg.tests && strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return build(&cfg, fset, files), nil
+}
+
+// ParseFiles returns the node for the package of files, which were parsed into fset.
+func ParseFiles(fset *token.FileSet, files []*ast.File, opts ...Option) *Node {
+	cfg := config{styles: DefaultStyles()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return build(&cfg, fset, files)
+}
+
+func build(cfg *config, fset *token.FileSet, files []*ast.File) *Node {
+	pkg := &Node{cfg: cfg, kind: Package}
+	for _, f := range files {
+		if pkg.name == "" {
+			pkg.name = f.Name.Name
+			pkg.pos = fset.Position(f.Package)
+		}
+		fn := &Node{cfg: cfg, parent: pkg, kind: File, pos: fset.Position(f.Package)}
+		fn.name = filepath.Base(fn.pos.Filename)
+		fn.addDecls(fset, f)
+		pkg.children = append(pkg.children, fn)
+	}
+	slices.SortStableFunc(pkg.children, func(a, b *Node) int {
+		return cmp.Compare(a.name, b.name)
+	})
+	for _, c := range pkg.children {
+		if len(c.children) > 0 {
+			c.state = tree.NodeCollapsible
+		}
+	}
+	if len(pkg.children) > 0 {
+		pkg.state = tree.NodeCollapsible
+	}
+	return pkg
+}
+
+// addDecls adds the declarations of the file f to the file node n.
+func (n *Node) addDecls(fset *token.FileSet, f *ast.File) {
+	types := make(map[string]*Node)
+	var methods []*ast.FuncDecl
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if c := n.add(Type, s.Name, fset); c != nil {
+						types[c.name] = c
+					}
+				case *ast.ValueSpec:
+					kind := Var
+					if d.Tok == token.CONST {
+						kind = Const
+					}
+					for _, id := range s.Names {
+						n.add(kind, id, fset)
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				n.add(Func, d.Name, fset)
+				continue
+			}
+			methods = append(methods, d)
+		}
+	}
+	// The methods are added after all the types, as they can be declared before their receiver.
+	for _, d := range methods {
+		recv := receiverName(d.Recv.List[0].Type)
+		if n.cfg.exportedOnly && !token.IsExported(recv) {
+			continue
+		}
+		parent, ok := types[recv]
+		if !ok {
+			parent = &Node{cfg: n.cfg, parent: n, kind: Receiver, name: recv, pos: fset.Position(d.Pos())}
+			types[recv] = parent
+			n.children = append(n.children, parent)
+		}
+		parent.add(Method, d.Name, fset)
+	}
+	for _, t := range types {
+		if len(t.children) > 0 {
+			t.state = tree.NodeCollapsible | tree.NodeCollapsed
+		}
+	}
+	slices.SortStableFunc(n.children, func(a, b *Node) int {
+		return cmp.Compare(a.pos.Offset, b.pos.Offset)
+	})
+}
+
+// add appends a declaration node for the identifier id, unless it is filtered out.
+func (n *Node) add(kind Kind, id *ast.Ident, fset *token.FileSet) *Node {
+	if id.Name == "_" || (n.cfg.exportedOnly && !id.IsExported()) {
+		return nil
+	}
+	c := &Node{cfg: n.cfg, parent: n, kind: kind, name: id.Name, pos: fset.Position(id.Pos())}
+	n.children = append(n.children, c)
+	return c
+}
+
+// receiverName returns the name of the type of a method receiver.
+func receiverName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// Kind returns the kind of declaration of the node.
+func (n *Node) Kind() Kind {
+	return n.kind
+}
+
+// Name returns the name of the declaration, file or package.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Title returns the name of the node, to be used by the tree.Breadcrumb.
+func (n *Node) Title() string {
+	return n.name
+}
+
+// Position returns the position of the declared identifier, or of the package clause
+// for the file and package nodes.
+func (n *Node) Position() token.Position {
+	return n.pos
+}
+
+func (n *Node) View() tea.View {
+	s := n.cfg.styles
+	name := s.Name.Render(n.name)
+	switch n.kind {
+	case File:
+		return tea.NewView(name)
+	case Receiver:
+		return tea.NewView(s.Keyword.Render("methods of") + " " + name)
+	}
+	return tea.NewView(s.Keyword.Render(n.kind.String()) + " " + name)
+}
+
+func (n *Node) Parent() tree.Node {
+	if n == nil || n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Init() tea.Cmd {
+	return nil
+}
+
+func (n *Node) Children() tree.Nodes {
+	if len(n.children) == 0 {
+		return nil
+	}
+	nodes := make(tree.Nodes, len(n.children))
+	for i, c := range n.children {
+		nodes[i] = c
+	}
+	return nodes
+}
+
+func (n *Node) State() tree.NodeState {
+	return n.state
+}
+
+// Update sets the state of the node, and emits a SelectedMsg when the node gets selected.
+func (n *Node) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tree.NodeState:
+		selected := !n.state.Is(tree.NodeSelected) && m.Is(tree.NodeSelected)
+		n.state = m
+		if selected && n.pos.IsValid() {
+			return n, n.selected
+		}
+	}
+	return n, nil
+}
+
+func (n *Node) selected() tea.Msg {
+	return SelectedMsg{Node: n, Position: n.pos}
+}
@@ -0,0 +1,230 @@
+package goast
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+var testFiles = map[string]string{
+	"model.go": `package demo
+
+// the methods come before their type
+func (m *Model) Update() {}
+
+type Model struct{}
+
+func (m Model) View() string { return "" }
+
+func (s *store[K, V]) Get(k K) V { var v V; return v }
+
+func New() *Model { return nil }
+
+const (
+	A = iota
+	b
+	_
+)
+`,
+	"store.go": `package demo
+
+var x, Y int
+
+type store[K comparable, V any] struct{}
+`,
+}
+
+// parseTestFiles parses the testFiles, in a deterministic order.
+func parseTestFiles(t *testing.T, opts ...Option) *Node {
+	t.Helper()
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0)
+	for _, name := range []string{"store.go", "model.go"} {
+		f, err := parser.ParseFile(fset, name, testFiles[name], 0)
+		if err != nil {
+			t.Fatalf("ParseFile() error = %v", err)
+		}
+		files = append(files, f)
+	}
+	return ParseFiles(fset, files, append([]Option{WithStyles(Styles{})}, opts...)...)
+}
+
+// views returns the views of all the nodes, with the children indented.
+func views(nodes tree.Nodes) []string {
+	result := make([]string, 0)
+	for _, n := range nodes {
+		result = append(result, n.View().Content)
+		for _, c := range views(n.Children()) {
+			result = append(result, "  "+c)
+		}
+	}
+	return result
+}
+
+func TestParseFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "all",
+			want: []string{
+				"package demo",
+				"  model.go",
+				"    type Model",
+				"      func Update",
+				"      func View",
+				"    methods of store",
+				"      func Get",
+				"    func New",
+				"    const A",
+				"    const b",
+				"  store.go",
+				"    var x",
+				"    var Y",
+				"    type store",
+			},
+		},
+		{
+			name: "exported only",
+			opts: []Option{ExportedOnly()},
+			want: []string{
+				"package demo",
+				"  model.go",
+				"    type Model",
+				"      func Update",
+				"      func View",
+				"    func New",
+				"    const A",
+				"  store.go",
+				"    var Y",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := parseTestFiles(t, tt.opts...)
+			if diff := cmp.Diff(tt.want, views(tree.Nodes{n})); diff != "" {
+				t.Errorf("ParseFiles() views mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNode_Position(t *testing.T) {
+	n := parseTestFiles(t)
+	model := n.children[0]
+	tests := []struct {
+		name string
+		node *Node
+		want string
+	}{
+		{name: "file", node: model, want: "model.go:1:1"},
+		{name: "type", node: model.children[0], want: "model.go:6:6"},
+		{name: "method", node: model.children[0].children[0], want: "model.go:4:17"},
+		{name: "receiver", node: model.children[1], want: "model.go:10:1"},
+		{name: "const", node: model.children[3], want: "model.go:15:2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.Position().String(); got != tt.want {
+				t.Errorf("Position() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range testFiles {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	test := "package demo\n\nfunc TestX() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "demo_test.go"), []byte(test), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{name: "default", want: []string{"model.go", "store.go"}},
+		{name: "tests", opts: []Option{IncludeTests()}, want: []string{"demo_test.go", "model.go", "store.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseDir(dir, tt.opts...)
+			if err != nil {
+				t.Fatalf("ParseDir() error = %v", err)
+			}
+			got := make([]string, 0)
+			for _, c := range n.children {
+				got = append(got, c.Name())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseDir() files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := ParseDir(t.TempDir()); err == nil {
+		t.Errorf("ParseDir() of an empty directory should fail")
+	}
+}
+
+// messages runs the command, returning all the messages of the batches it contains.
+func messages(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		result := make([]tea.Msg, 0)
+		for _, c := range batch {
+			result = append(result, messages(c)...)
+		}
+		return result
+	}
+	return []tea.Msg{msg}
+}
+
+func TestNode_Update_selected(t *testing.T) {
+	n := parseTestFiles(t)
+	m := tree.New(tree.Nodes{n})
+	m.SetHeight(20)
+	m.SetWidth(40)
+	m.Init()
+
+	// package demo, model.go, type Model, methods of store
+	var got *SelectedMsg
+	for _, msg := range messages(m.SetCursor(3)) {
+		if s, ok := msg.(SelectedMsg); ok {
+			got = &s
+		}
+	}
+	if got == nil {
+		t.Fatalf("SetCursor() didn't emit a SelectedMsg")
+	}
+	if got.Node != n.children[0].children[1] {
+		t.Errorf("SelectedMsg.Node = %v", got.Node.View().Content)
+	}
+	if got.String() != "model.go:10" {
+		t.Errorf("SelectedMsg.String() = %q, want %q", got.String(), "model.go:10")
+	}
+
+	// selecting the same node again doesn't emit anything
+	if _, cmd := got.Node.Update(got.Node.State()); cmd != nil {
+		t.Errorf("Update() of a selected node returned a command")
+	}
+}
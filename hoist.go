@@ -0,0 +1,142 @@
+package tree
+
+import (
+	tea "charm.land/bubbletea/v2"
+)
+
+// HoistedMsg is emitted when the tree Model is hoisted into a node, or unhoisted.
+// Node is the node currently acting as the root of the tree, or nil when the whole tree is shown.
+type HoistedMsg struct {
+	Node
+}
+
+// hoist holds a hoisted node and the state of the Model from before it was hoisted.
+type hoist struct {
+	node Node
+	tree Nodes
+	// selected is the node which was selected, and cursor its index, before the hoisting.
+	selected Node
+	cursor   int
+	yOffset  int
+}
+
+// Hoist shows only the descendants of the node n, as if n was the root of the tree.
+// The depth of the nodes, and their tree symbols, are computed relative to n.
+// Hoists can be nested, and every call to Unhoist reverts the last one.
+// The nodes without children can't be hoisted into.
+func (m *Model) Hoist(n Node) tea.Cmd {
+	if n == nil || len(n.Children()) == 0 {
+		return noop
+	}
+	m.hoisted = append(m.hoisted, hoist{node: n, tree: m.tree, selected: m.currentNode(), cursor: m.cursor, yOffset: m.YOffset()})
+	return tea.Batch(m.replaceTree(n.Children(), 0, 0), m.hoistChanged)
+}
+
+// HoistCurrent hoists the tree into the current node.
+func (m *Model) HoistCurrent() tea.Cmd {
+	return m.Hoist(m.currentNode())
+}
+
+// Unhoist reverts the last Hoist, selecting again the node which was selected before it.
+// When that node is not visible anymore, the node which took its place gets selected.
+func (m *Model) Unhoist() tea.Cmd {
+	if len(m.hoisted) == 0 {
+		return noop
+	}
+	h := m.hoisted[len(m.hoisted)-1]
+	m.hoisted = m.hoisted[:len(m.hoisted)-1]
+
+	// NOTE(marius): the nodes can have changed while hoisted, like when a watcher updates them
+	t := h.tree
+	if p := m.hoistedNode(); p != nil {
+		t = p.Children()
+	}
+	cursor := h.cursor
+	if i := t.sequentialNodes().indexOf(h.selected); i >= 0 {
+		cursor = i
+	}
+	return tea.Batch(m.replaceTree(t, cursor, h.yOffset), m.hoistChanged)
+}
+
+// Hoisted returns the stack of hoisted nodes, the last one being the current root of the tree.
+func (m *Model) Hoisted() Nodes {
+	nodes := make(Nodes, len(m.hoisted))
+	for i, h := range m.hoisted {
+		nodes[i] = h.node
+	}
+	return nodes
+}
+
+// hoistedNode returns the node currently acting as the root of the tree, if any.
+func (m *Model) hoistedNode() Node {
+	if len(m.hoisted) == 0 {
+		return nil
+	}
+	return m.hoisted[len(m.hoisted)-1].node
+}
+
+func (m *Model) hoistChanged() tea.Msg {
+	return HoistedMsg{Node: m.hoistedNode()}
+}
+
+// replaceTree swaps the top level nodes of the Model, moving the selection to cursor.
+func (m *Model) replaceTree(t Nodes, cursor, yOffset int) tea.Cmd {
+	if cur := m.currentNode(); cur != nil {
+		cur.Update(cur.State() &^ NodeSelected)
+	}
+	m.tree = t
+	m.cursor = -1
+	m.xOffset = 0
+	m.Model.SetYOffset(yOffset)
+	return m.SetCursor(cursor)
+}
+
+// refreshHoisted reloads the top level nodes of a hoisted tree from its root,
+// as their parent can change them after the hoisting.
+func (m *Model) refreshHoisted() {
+	if h := m.hoistedNode(); h != nil {
+		m.tree = h.Children()
+	}
+}
+
+// hoistDepth returns the depth of the top level nodes in the full tree.
+func (m *Model) hoistDepth() int {
+	if h := m.hoistedNode(); h != nil {
+		return getDepth(h) + 1
+	}
+	return 0
+}
+
+// depth returns the depth of node n relative to the current root of the tree.
+func (m *Model) depth(n Node) int {
+	return max(0, getDepth(n)-m.hoistDepth())
+}
+
+// ancestors returns the ancestors of node n which are visible in the tree,
+// ordered from the top level down.
+func (m *Model) ancestors(n Node) Nodes {
+	chain := ancestors(n)
+	if d := m.hoistDepth(); d <= len(chain) {
+		return chain[d:]
+	}
+	return chain
+}
+
+// gotoNode moves the selection to node n, unhoisting the tree until n is one of its descendants.
+func (m *Model) gotoNode(n Node) tea.Cmd {
+	var cmds []tea.Cmd
+	for h := m.hoistedNode(); h != nil && !isDescendant(n, h); h = m.hoistedNode() {
+		cmds = append(cmds, m.Unhoist())
+	}
+	return tea.Batch(append(cmds, m.GotoNode(n))...)
+}
+
+// isDescendant checks if node n is in the subtree of node p.
+func isDescendant(n, p Node) bool {
+	for n != nil {
+		if n = n.Parent(); n == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/go-cmp/cmp"
+)
+
+func hoistedModel() (*Model, *n) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.SetWidth(26)
+	m.SetHeight(12)
+	return m, tree
+}
+
+func viewLines(m *Model) []string {
+	lines := strings.Split(ansi.Strip(m.View().Content), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func TestModel_Hoist(t *testing.T) {
+	m, tree := hoistedModel()
+	m.SetCursor(2)
+	test := tree.c[1]
+
+	m.Hoist(test)
+
+	want := []string{
+		"├─ example",
+		"│  ├─ file2",
+		"│  ├─ file4",
+		"│  └─ lastchild",
+		"│     └─ file",
+		"├─ file1",
+		"├─ file3",
+		"└─ file5",
+	}
+	if diff := cmp.Diff(want, viewLines(m)); diff != "" {
+		t.Errorf("View() after Hoist() mismatch (-want +got):\n%s", diff)
+	}
+	if got := m.CurrentNode(); got != test.c[0] {
+		t.Errorf("CurrentNode() = %v, want the first child of the hoisted node", got)
+	}
+	if isSelected(test) {
+		t.Errorf("the hoisted node is still selected")
+	}
+	if got := m.depth(test.c[0].c[2].c[0]); got != 2 {
+		t.Errorf("depth() = %d, want 2", got)
+	}
+	if got := names(m.Hoisted()); !cmp.Equal(got, []string{"test"}) {
+		t.Errorf("Hoisted() = %v, want [test]", got)
+	}
+}
+
+func TestModel_Unhoist(t *testing.T) {
+	m, tree := hoistedModel()
+	m.SetCursor(2)
+	test := tree.c[1]
+	example := test.c[0]
+
+	m.Hoist(test)
+	m.SetCursor(3)
+	m.Hoist(example.c[2])
+	if got := names(m.Hoisted()); !cmp.Equal(got, []string{"test", "lastchild"}) {
+		t.Fatalf("Hoisted() = %v, want [test lastchild]", got)
+	}
+	if diff := cmp.Diff([]string{"└─ file"}, viewLines(m)); diff != "" {
+		t.Errorf("View() after nested Hoist() mismatch (-want +got):\n%s", diff)
+	}
+
+	m.Unhoist()
+	if got := m.CurrentNode(); got != example.c[2] {
+		t.Errorf("CurrentNode() after Unhoist() = %v, want lastchild", got)
+	}
+	m.Unhoist()
+	if got := m.CurrentNode(); got != test {
+		t.Errorf("CurrentNode() after second Unhoist() = %v, want test", got)
+	}
+	if len(m.Hoisted()) != 0 {
+		t.Errorf("Hoisted() = %v, want empty", m.Hoisted())
+	}
+	if got := viewLines(m); len(got) != 11 || got[0] != "└─ tmp" {
+		t.Errorf("View() after Unhoist() = %v, want the full tree", got)
+	}
+	if cmd := m.Unhoist(); cmd != nil {
+		t.Errorf("Unhoist() on an unhoisted tree returned a command")
+	}
+}
+
+func TestModel_Hoist_messages(t *testing.T) {
+	m, tree := hoistedModel()
+	test := tree.c[1]
+
+	m.Hoist(test)
+	if got := m.hoistChanged(); got != (HoistedMsg{Node: test}) {
+		t.Errorf("hoistChanged() = %v, want HoistedMsg for test", got)
+	}
+	m.Unhoist()
+	if got := m.hoistChanged(); got != (HoistedMsg{}) {
+		t.Errorf("hoistChanged() = %v, want empty HoistedMsg", got)
+	}
+}
+
+func TestModel_gotoNode_unhoists(t *testing.T) {
+	m, tree := hoistedModel()
+	test := tree.c[1]
+	example := test.c[0]
+
+	m.Hoist(test)
+	m.Hoist(example)
+	m.Update(GotoNodeMsg{Node: test.c[1]})
+
+	if got := names(m.Hoisted()); !cmp.Equal(got, []string{"test"}) {
+		t.Errorf("Hoisted() = %v, want [test]", got)
+	}
+	if got := m.CurrentNode(); got != test.c[1] {
+		t.Errorf("CurrentNode() = %v, want file1", got)
+	}
+}
+
+func TestModel_Status_hoisted(t *testing.T) {
+	m, tree := hoistedModel()
+	m.Hoist(tree.c[1])
+	m.SetCursor(1)
+
+	if got, want := m.Status(), "row 2 of 8, depth 1, 0 marked"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+}
+
+func TestModel_Hoist_leaf(t *testing.T) {
+	m, tree := hoistedModel()
+	m.SetCursor(1)
+
+	if cmd := m.Hoist(tree.c[0]); cmd != nil {
+		t.Errorf("Hoist() of a leaf returned a command")
+	}
+	if len(m.Hoisted()) != 0 {
+		t.Errorf("Hoisted() = %v, want empty", names(m.Hoisted()))
+	}
+	if got := m.CurrentNode(); got != tree.c[0] {
+		t.Errorf("CurrentNode() = %v, want example1", got)
+	}
+}
+
+func TestModel_Unhoist_changed(t *testing.T) {
+	m, tree := hoistedModel()
+	m.SetCursor(2)
+	test := tree.c[1]
+
+	m.Hoist(test)
+	// remove example1, which is before test, while hoisted
+	tree.c = tree.c[1:]
+	m.Unhoist()
+
+	if got := m.CurrentNode(); got != test {
+		t.Errorf("CurrentNode() after Unhoist() = %v, want test", got.View().Content)
+	}
+	if got := m.Cursor(); got != 1 {
+		t.Errorf("Cursor() after Unhoist() = %d, want 1", got)
+	}
+}
@@ -0,0 +1,180 @@
+package tree
+
+import (
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Iconer is implemented by nodes which provide their own icon, based on their state.
+type Iconer interface {
+	Icon(NodeState) string
+}
+
+// IconProvider returns the icons for the nodes which don't implement Iconer.
+type IconProvider interface {
+	Icon(Node) string
+}
+
+// IconProviderFunc is an adapter to allow the use of ordinary functions as IconProvider.
+type IconProviderFunc func(Node) string
+
+// Icon returns f(n).
+func (f IconProviderFunc) Icon(n Node) string {
+	return f(n)
+}
+
+// icon returns the icon of node n, asking the node itself first and the provider after.
+func (m *Model) icon(n Node) string {
+	if i, ok := n.(Iconer); ok {
+		return i.Icon(n.State())
+	}
+	if m.Icons == nil {
+		return ""
+	}
+	return m.Icons.Icon(n)
+}
+
+// updateIconWidth computes the width of the icon column from the widest icon of the nodes,
+// including the gap before the content. It is zero when none of the nodes has an icon.
+func (m *Model) updateIconWidth(nodes Nodes) {
+	m.iconWidth = 0
+	w := 0
+	for _, n := range nodes {
+		w = max(w, ansi.StringWidth(m.icon(n)))
+	}
+	if w > 0 {
+		m.iconWidth = w + 1
+	}
+}
+
+// renderIcon renders the icon column for node n, spanning lineCount lines.
+// Only the first line contains the icon.
+func (m *Model) renderIcon(n Node, lineCount int) string {
+	lines := make([]string, max(1, lineCount))
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", m.iconWidth)
+	}
+	lines[0] = m.Styles.Icon.Width(m.iconWidth).Render(m.icon(n))
+	return strings.Join(lines, "\n")
+}
+
+// IconSet maps file names and extensions to icons.
+type IconSet struct {
+	Directory     string
+	DirectoryOpen string
+	File          string
+	// Names holds icons for specific file names, which take precedence over the extensions.
+	Names map[string]string
+	// Extensions holds icons for file extensions, in lower case and without the leading dot.
+	Extensions map[string]string
+}
+
+// NerdFontIcons is an IconSet using glyphs from the Nerd Fonts (https://www.nerdfonts.com).
+var NerdFontIcons = IconSet{
+	Directory:     "",
+	DirectoryOpen: "",
+	File:          "",
+	Names: map[string]string{
+		".gitignore": "",
+		"go.mod":     "",
+		"go.sum":     "",
+		"LICENSE":    "",
+		"Makefile":   "",
+		"Dockerfile": "",
+	},
+	Extensions: map[string]string{
+		"go":   "",
+		"md":   "",
+		"json": "",
+		"js":   "",
+		"html": "",
+		"css":  "",
+		"py":   "",
+		"rs":   "",
+		"sh":   "",
+		"yml":  "",
+		"yaml": "",
+		"toml": "",
+		"txt":  "",
+		"pdf":  "",
+		"png":  "",
+		"jpg":  "",
+		"gif":  "",
+		"svg":  "",
+		"zip":  "",
+		"tar":  "",
+		"gz":   "",
+		"lock": "",
+	},
+}
+
+// UnicodeIcons is an IconSet using emoji available in most terminal fonts.
+var UnicodeIcons = IconSet{
+	Directory:     "📁",
+	DirectoryOpen: "📂",
+	File:          "📄",
+	Names: map[string]string{
+		"LICENSE":  "📜",
+		"Makefile": "🔧",
+	},
+	Extensions: map[string]string{
+		"go":   "🐹",
+		"md":   "📝",
+		"txt":  "📝",
+		"py":   "🐍",
+		"rs":   "🦀",
+		"html": "🌐",
+		"css":  "🎨",
+		"sh":   "💲",
+		"yml":  "🔧",
+		"yaml": "🔧",
+		"toml": "🔧",
+		"json": "🔧",
+		"pdf":  "📕",
+		"png":  "📷",
+		"jpg":  "📷",
+		"gif":  "📷",
+		"svg":  "📷",
+		"zip":  "📦",
+		"tar":  "📦",
+		"gz":   "📦",
+		"lock": "🔒",
+	},
+}
+
+// FileIcons is an IconProvider for filesystem trees, which picks the icons based on the
+// file names. The name of a node is its Title if it implements Titler, or its View otherwise.
+// Nodes are considered to be directories if they have an IsDir method returning true,
+// or, in its absence, if they can have children.
+type FileIcons struct {
+	Set IconSet
+}
+
+// Icon returns the icon for the file represented by node n.
+func (f FileIcons) Icon(n Node) string {
+	if isDir(n) {
+		if isExpanded(n) && f.Set.DirectoryOpen != "" {
+			return f.Set.DirectoryOpen
+		}
+		return f.Set.Directory
+	}
+	name := path.Base(title(n))
+	if i, ok := f.Set.Names[name]; ok {
+		return i
+	}
+	if ext := strings.ToLower(strings.TrimPrefix(path.Ext(name), ".")); ext != "" {
+		if i, ok := f.Set.Extensions[ext]; ok {
+			return i
+		}
+	}
+	return f.Set.File
+}
+
+func isDir(n Node) bool {
+	if d, ok := n.(interface{ IsDir() bool }); ok {
+		return d.IsDir()
+	}
+	return isCollapsible(n) || len(n.Children()) > 0
+}
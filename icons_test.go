@@ -0,0 +1,117 @@
+package tree
+
+import (
+	"testing"
+)
+
+type iconNode struct {
+	*n
+}
+
+func (i iconNode) Icon(st NodeState) string {
+	if st.Is(NodeCollapsed) {
+		return "★"
+	}
+	return "☆"
+}
+
+func TestFileIcons_Icon(t *testing.T) {
+	set := IconSet{
+		Directory:     "D",
+		DirectoryOpen: "O",
+		File:          "F",
+		Names:         map[string]string{"go.mod": "M"},
+		Extensions:    map[string]string{"go": "G", "md": "W"},
+	}
+	tests := []struct {
+		name string
+		node Node
+		want string
+	}{
+		{name: "collapsed directory", node: tn("dir", st(NodeCollapsed), c(tn("file"))), want: "D"},
+		{name: "expanded directory", node: tn("dir", c(tn("file"))), want: "O"},
+		{name: "empty collapsible directory", node: tn("dir", st(NodeCollapsible|NodeCollapsed)), want: "D"},
+		{name: "file name", node: tn("go.mod"), want: "M"},
+		{name: "extension", node: tn("main.go"), want: "G"},
+		{name: "upper case extension", node: tn("README.MD"), want: "W"},
+		{name: "path", node: tn("some/path/main.go"), want: "G"},
+		{name: "unknown extension", node: tn("main.c"), want: "F"},
+		{name: "no extension", node: tn("main"), want: "F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (FileIcons{Set: set}).Icon(tt.node); got != tt.want {
+				t.Errorf("Icon() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_RenderAll_icons(t *testing.T) {
+	tests := []struct {
+		name  string
+		icons IconProvider
+		tree  func() Nodes
+		want  string
+	}{
+		{
+			name:  "no icons",
+			icons: nil,
+			tree: func() Nodes {
+				return Nodes{tn("one", st(NodeLastChild), c(tn("two")))}
+			},
+			want: "└─ one\n" +
+				"   └─ two",
+		},
+		{
+			name:  "wide icons are aligned",
+			icons: FileIcons{Set: UnicodeIcons},
+			tree: func() Nodes {
+				return Nodes{tn("dir", st(NodeLastChild), c(tn("main.go"), tn("main.c")))}
+			},
+			want: "└─ 📂 dir\n" +
+				"   ├─ 🐹 main.go\n" +
+				"   └─ 📄 main.c",
+		},
+		{
+			name: "mixed widths",
+			icons: IconProviderFunc(func(n Node) string {
+				if title(n) == "wide" {
+					return "界"
+				}
+				return "*"
+			}),
+			tree: func() Nodes {
+				return Nodes{tn("one", st(NodeLastChild), c(tn("wide"), tn("narrow")))}
+			},
+			want: "└─ *  one\n" +
+				"   ├─ 界 wide\n" +
+				"   └─ *  narrow",
+		},
+		{
+			name:  "iconers take precedence",
+			icons: FileIcons{Set: UnicodeIcons},
+			tree: func() Nodes {
+				return Nodes{iconNode{n: tn("star.go", st(NodeLastChild|NodeCollapsed))}}
+			},
+			want: "└─ ★ star.go",
+		},
+		{
+			name:  "iconers without a provider",
+			icons: nil,
+			tree: func() Nodes {
+				return Nodes{iconNode{n: tn("star.go")}, tn("plain", st(NodeLastChild))}
+			},
+			want: "├─ ☆ star.go\n" +
+				"└─   plain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(tt.tree())
+			if got := m.RenderAll(WithIcons(tt.icons)); got != tt.want {
+				t.Errorf("RenderAll() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Package size formats the sizes in bytes shown by the node adapters.
+package size
+
+import "fmt"
+
+// Format formats a size in bytes using binary units, like "1.5 KiB".
+func Format(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,22 @@
+package size
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 1023, want: "1023 B"},
+		{size: 1024, want: "1.0 KiB"},
+		{size: 1536, want: "1.5 KiB"},
+		{size: 5 << 20, want: "5.0 MiB"},
+		{size: 3 << 30, want: "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.size); got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,603 @@
+// Package jsontree provides tree nodes for exploring JSON documents.
+//
+// Objects and arrays are collapsible nodes, which show the number of their elements
+// when collapsed, and scalars are rendered with syntax colors:
+//
+//	root, err := jsontree.Decode(os.Stdin)
+//	if err != nil {
+//		return err
+//	}
+//	t := tree.New(tree.Nodes{root})
+//
+// The documents read with Decode are streamed: the elements of the arrays and objects, at any
+// depth, are read from the json.Decoder in pages, as they are needed, so that large API responses
+// and dumps, like {"data": [...]}, don't have to be read whole before they can be explored.
+package jsontree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// Kind is the JSON type of a Node.
+type Kind int
+
+const (
+	Null Kind = iota
+	Bool
+	Number
+	String
+	Array
+	Object
+	// more is the kind of the node which loads the next page of a streamed array.
+	more
+)
+
+// DefaultPageSize is the number of elements read at once from a streamed array or object.
+const DefaultPageSize = 100
+
+// Styles contains the style definitions for the JSON syntax.
+type Styles struct {
+	Key    lipgloss.Style
+	Index  lipgloss.Style
+	String lipgloss.Style
+	Number lipgloss.Style
+	Bool   lipgloss.Style
+	Null   lipgloss.Style
+	// Count is used for the number of elements of the collapsed objects and arrays.
+	Count lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for the JSON syntax.
+func DefaultStyles() Styles {
+	return Styles{
+		Key:    lipgloss.NewStyle().Foreground(lipgloss.Blue),
+		Index:  lipgloss.NewStyle().Faint(true),
+		String: lipgloss.NewStyle().Foreground(lipgloss.Green),
+		Number: lipgloss.NewStyle().Foreground(lipgloss.Cyan),
+		Bool:   lipgloss.NewStyle().Foreground(lipgloss.Yellow),
+		Null:   lipgloss.NewStyle().Foreground(lipgloss.Magenta),
+		Count:  lipgloss.NewStyle().Faint(true),
+	}
+}
+
+type config struct {
+	styles      Styles
+	pageSize    int
+	expandDepth int
+}
+
+// Option configures how the nodes are built from the JSON document.
+type Option func(*config)
+
+// WithStyles sets the Styles used for rendering the nodes.
+func WithStyles(s Styles) Option {
+	return func(c *config) {
+		c.styles = s
+	}
+}
+
+// WithPageSize sets the number of elements read at once from a streamed array or object.
+func WithPageSize(size int) Option {
+	return func(c *config) {
+		c.pageSize = max(1, size)
+	}
+}
+
+// ExpandDepth sets the depth up to which the objects and arrays are initially expanded.
+// By default, only the root is expanded.
+func ExpandDepth(depth int) Option {
+	return func(c *config) {
+		c.expandDepth = depth
+	}
+}
+
+// Node is a tree.Node for a JSON value.
+type Node struct {
+	cfg *config
+
+	parent *Node
+	// key is the name of the object member, which is empty for the array elements and the root.
+	key string
+	// index is the position in the parent array, or -1.
+	index int
+	kind  Kind
+	// raw holds the JSON text of the scalar values.
+	raw      string
+	children []*Node
+	state    tree.NodeState
+
+	// stream is set for an array or object whose remaining elements are still to be read.
+	stream *stream
+	// next is the node loading the next page of a streamed array or object.
+	next *Node
+	err  error
+}
+
+// stream is the decoder of a document read by Decode, which is shared by its nodes.
+// The values are read in the order of the document, so only the innermost of the open
+// arrays and objects, the last one in open, can read its next elements.
+type stream struct {
+	dec  *json.Decoder
+	open []*Node
+}
+
+func newConfig(opts []Option) *config {
+	cfg := config{styles: DefaultStyles(), pageSize: DefaultPageSize, expandDepth: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &cfg
+}
+
+// Parse builds the nodes for the JSON document in data, keeping the order of the object members.
+func Parse(data []byte, opts ...Option) (*Node, error) {
+	cfg := newConfig(opts)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	n, err := build(dec, cfg, nil, "", -1)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid data after the top-level value")
+	}
+	return n, nil
+}
+
+// FromValue builds the nodes for any value which can be encoded by json.Marshal.
+func FromValue(v any, opts ...Option) (*Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, opts...)
+}
+
+// Decode builds the nodes for the JSON document read from r. The elements of its arrays and
+// objects are read lazily, in pages, and every time the last node of one of them, which loads
+// its next page, is expanded. The nested arrays and objects read their first page as soon as
+// they are read themselves, while the root reads it the first time it's expanded.
+//
+// When the next page of an array or object is loaded while one of its elements is still being
+// streamed, the rest of that element is first read into memory, without building its nodes.
+// The errors found after the first value are returned by the Err method of the nodes.
+func Decode(r io.Reader, opts ...Option) (*Node, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	s := &stream{dec: dec}
+	return s.read(newConfig(opts), nil, "", -1)
+}
+
+// read reads the next value from the decoder. The arrays and objects are left open, to be
+// read by their loadPage method.
+func (s *stream) read(cfg *config, parent *Node, key string, index int) (*Node, error) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{cfg: cfg, parent: parent, key: key, index: index}
+	switch tok {
+	case json.Delim('['):
+		n.kind = Array
+	case json.Delim('{'):
+		n.kind = Object
+	default:
+		return buildToken(s.dec, tok, cfg, parent, key, index)
+	}
+	n.stream = s
+	n.next = &Node{cfg: cfg, parent: n, index: -1, kind: more, state: tree.NodeCollapsible | tree.NodeCollapsed}
+	n.state = tree.NodeCollapsible | tree.NodeCollapsed
+	if n.depth() < cfg.expandDepth {
+		n.state &^= tree.NodeCollapsed
+	}
+	s.open = append(s.open, n)
+	if parent != nil || !n.state.Is(tree.NodeCollapsed) {
+		n.loadPage()
+	}
+	return n, nil
+}
+
+// detach reads the rest of the innermost open array or object into memory, without building
+// its nodes, so that the decoder can read the values after it. Its next elements are then read
+// from a decoder of their own.
+func (s *stream) detach() error {
+	n := s.open[len(s.open)-1]
+	open, closed := byte('['), byte(']')
+	if n.kind == Object {
+		open, closed = '{', '}'
+	}
+	buf := bytes.Buffer{}
+	buf.WriteByte(open)
+	for i := 0; s.dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if n.kind == Object {
+			tok, err := s.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := json.Marshal(tok)
+			buf.Write(key)
+			buf.WriteByte(':')
+		}
+		var raw json.RawMessage
+		if err := s.dec.Decode(&raw); err != nil {
+			return err
+		}
+		buf.Write(raw)
+	}
+	// the closing delimiter
+	if _, err := s.dec.Token(); err != nil {
+		return err
+	}
+	buf.WriteByte(closed)
+	s.open = s.open[:len(s.open)-1]
+
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+	// the opening delimiter, which was already read from the document
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	n.stream = &stream{dec: dec, open: []*Node{n}}
+	return nil
+}
+
+// fail stops the reading of all the open arrays and objects, which report err from then on.
+func (s *stream) fail(err error) {
+	for _, n := range s.open {
+		n.err = err
+		n.stream = nil
+	}
+	s.open = nil
+}
+
+// build reads the next value from the decoder.
+func build(dec *json.Decoder, cfg *config, parent *Node, key string, index int) (*Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return buildToken(dec, tok, cfg, parent, key, index)
+}
+
+// buildToken builds the node for the value starting with tok, reading the rest of it from the decoder.
+func buildToken(dec *json.Decoder, tok json.Token, cfg *config, parent *Node, key string, index int) (*Node, error) {
+	n := &Node{cfg: cfg, parent: parent, key: key, index: index}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n.kind = Object
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				c, err := build(dec, cfg, n, kt.(string), -1)
+				if err != nil {
+					return nil, err
+				}
+				n.children = append(n.children, c)
+			}
+		case '[':
+			n.kind = Array
+			for i := 0; dec.More(); i++ {
+				c, err := build(dec, cfg, n, "", i)
+				if err != nil {
+					return nil, err
+				}
+				n.children = append(n.children, c)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %s", t)
+		}
+		// the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		if len(n.children) > 0 {
+			n.state = tree.NodeCollapsible
+			if n.depth() >= cfg.expandDepth {
+				n.state |= tree.NodeCollapsed
+			}
+		}
+	case string:
+		n.kind = String
+		raw, _ := json.Marshal(t)
+		n.raw = string(raw)
+	case json.Number:
+		n.kind = Number
+		n.raw = t.String()
+	case bool:
+		n.kind = Bool
+		n.raw = strconv.FormatBool(t)
+	case nil:
+		n.kind = Null
+		n.raw = "null"
+	}
+	return n, nil
+}
+
+// loadPage reads the next page of elements of a streamed array or object. The page ends early
+// at an element which is not read whole, as the values after it can't be read before it.
+func (n *Node) loadPage() {
+	s := n.stream
+	if s == nil {
+		return
+	}
+	for s.open[len(s.open)-1] != n {
+		if err := s.detach(); err != nil {
+			s.fail(err)
+			return
+		}
+	}
+	for i := 0; i < n.cfg.pageSize && s.dec.More(); i++ {
+		key, index := "", len(n.children)
+		if n.kind == Object {
+			tok, err := s.dec.Token()
+			if err != nil {
+				s.fail(err)
+				return
+			}
+			key, index = tok.(string), -1
+		}
+		c, err := s.read(n.cfg, n, key, index)
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		n.children = append(n.children, c)
+		if c.stream != nil || n.stream == nil {
+			return
+		}
+	}
+	if s.dec.More() {
+		return
+	}
+	// the closing delimiter
+	if _, err := s.dec.Token(); err != nil {
+		s.fail(err)
+		return
+	}
+	s.open = s.open[:len(s.open)-1]
+	n.stream = nil
+	if len(n.children) == 0 {
+		n.state &^= tree.NodeCollapsible | tree.NodeCollapsed
+	}
+}
+
+func (n *Node) depth() int {
+	d := 0
+	for p := n.parent; p != nil; p = p.parent {
+		d++
+	}
+	return d
+}
+
+// Kind returns the JSON type of the value.
+func (n *Node) Kind() Kind {
+	return n.kind
+}
+
+// Key returns the name of the object member, which is empty for array elements and the root.
+func (n *Node) Key() string {
+	return n.key
+}
+
+// Index returns the position of the element in its parent array, or -1 when the parent is not an array.
+func (n *Node) Index() int {
+	return n.index
+}
+
+// Raw returns the JSON text of a scalar value.
+func (n *Node) Raw() string {
+	return n.raw
+}
+
+// Len returns the number of elements of an object or array. For a streamed one it is
+// the number of elements read so far.
+func (n *Node) Len() int {
+	return len(n.children)
+}
+
+// Err returns the error encountered when streaming the elements of an array or object.
+func (n *Node) Err() error {
+	return n.err
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Path returns the JSON path of the node, like $.a.b[3], using the bracket notation
+// for the member names which are not identifiers.
+func (n *Node) Path() string {
+	if n.parent == nil {
+		return "$"
+	}
+	p := n.parent.Path()
+	if n.kind == more {
+		return p
+	}
+	if n.index >= 0 {
+		return p + "[" + strconv.Itoa(n.index) + "]"
+	}
+	if identifier.MatchString(n.key) {
+		return p + "." + n.key
+	}
+	quoted, _ := json.Marshal(n.key)
+	return p + "[" + string(quoted) + "]"
+}
+
+// CopyPath returns a command which copies the JSON path of node n to the system clipboard.
+// It does nothing for nodes which are not from this package.
+func CopyPath(n tree.Node) tea.Cmd {
+	jn, ok := n.(*Node)
+	if !ok || jn == nil {
+		return nil
+	}
+	return tea.SetClipboard(jn.Path())
+}
+
+// Title returns the member name, or index, of the node, to be used by the tree.Breadcrumb.
+func (n *Node) Title() string {
+	switch {
+	case n.parent == nil:
+		return "$"
+	case n.index >= 0:
+		return "[" + strconv.Itoa(n.index) + "]"
+	}
+	return n.key
+}
+
+func (n *Node) label() string {
+	s := n.cfg.styles
+	switch {
+	case n.parent == nil:
+		return s.Key.Render("$")
+	case n.index >= 0:
+		return s.Index.Render(strconv.Itoa(n.index))
+	}
+	quoted, _ := json.Marshal(n.key)
+	if identifier.MatchString(n.key) {
+		quoted = []byte(n.key)
+	}
+	return s.Key.Render(string(quoted))
+}
+
+func (n *Node) count() string {
+	count := strconv.Itoa(len(n.children))
+	if n.stream != nil {
+		count += "+"
+	}
+	switch {
+	case n.kind == Object && len(n.children) == 1:
+		return count + " key"
+	case n.kind == Object:
+		return count + " keys"
+	case len(n.children) == 1 && n.stream == nil:
+		return count + " item"
+	}
+	return count + " items"
+}
+
+func (n *Node) View() tea.View {
+	s := n.cfg.styles
+	var v string
+	switch n.kind {
+	case more:
+		v = s.Count.Render(tree.Ellipsis + " load more")
+		if n.parent.err != nil {
+			v = s.Null.Render(n.parent.err.Error())
+		}
+		return tea.NewView(v)
+	case Object, Array:
+		open, closed := "{", "}"
+		if n.kind == Array {
+			open, closed = "[", "]"
+		}
+		switch {
+		case len(n.children) == 0 && n.stream == nil && n.err == nil:
+			v = n.label() + ": " + open + closed
+		case n.state.Is(tree.NodeCollapsed):
+			v = n.label() + ": " + open + tree.Ellipsis + closed + " " + s.Count.Render(n.count())
+		default:
+			v = n.label()
+		}
+	case String:
+		v = n.label() + ": " + s.String.Render(n.raw)
+	case Number:
+		v = n.label() + ": " + s.Number.Render(n.raw)
+	case Bool:
+		v = n.label() + ": " + s.Bool.Render(n.raw)
+	case Null:
+		v = n.label() + ": " + s.Null.Render(n.raw)
+	}
+	return tea.NewView(v)
+}
+
+func (n *Node) Parent() tree.Node {
+	if n == nil || n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Init() tea.Cmd {
+	return nil
+}
+
+func (n *Node) Children() tree.Nodes {
+	if len(n.children) == 0 && n.next == nil {
+		return nil
+	}
+	nodes := make(tree.Nodes, 0, len(n.children)+1)
+	for _, c := range n.children {
+		nodes = append(nodes, c)
+	}
+	// The error of a streamed array or object is shown in place of its next page.
+	if n.stream != nil || n.err != nil {
+		nodes = append(nodes, n.next)
+	}
+	return nodes
+}
+
+func (n *Node) State() tree.NodeState {
+	return n.state
+}
+
+// Update sets the state of the node. Expanding a streamed array or object, which has no
+// elements yet, loads its first page, and expanding its last node loads the next page.
+// The returned command notifies the tree Model of the change.
+func (n *Node) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tree.NodeState:
+		n.state = m
+		if m.Is(tree.NodeCollapsed) {
+			break
+		}
+		if n.kind == more {
+			n.state |= tree.NodeCollapsed
+			return n, n.loadMore()
+		}
+		if n.stream != nil && len(n.children) == 0 {
+			n.loadPage()
+			return n, n.added(0)
+		}
+	}
+	return n, nil
+}
+
+// loadMore loads the next page of the streamed array or object of the node. The selection moves
+// from the node to the first loaded element, which takes its place in the tree.
+func (n *Node) loadMore() tea.Cmd {
+	p := n.parent
+	loaded := len(p.children)
+	p.loadPage()
+	if n.state.Is(tree.NodeSelected) && len(p.children) > loaded {
+		n.state &^= tree.NodeSelected
+		first := p.children[loaded]
+		first.state |= tree.NodeSelected
+	}
+	return p.added(loaded)
+}
+
+// added returns a command notifying the tree Model of the elements of the node from index from on.
+func (n *Node) added(from int) tea.Cmd {
+	added := make(tree.Nodes, 0, len(n.children)-from)
+	for _, c := range n.children[from:] {
+		added = append(added, c)
+	}
+	return func() tea.Msg {
+		return tree.ChangedMsg{Added: added}
+	}
+}
@@ -0,0 +1,344 @@
+package jsontree
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+const doc = `{"name": "tree", "version": 1.5, "tags": ["go", "tui"], "deps": {}, "meta": {"a b": true, "empty": null}}`
+
+// plain renders the nodes without colors.
+var plain = WithStyles(Styles{})
+
+// views returns the views of the nodes, with the children of the expanded ones indented.
+func views(nodes tree.Nodes) []string {
+	result := make([]string, 0)
+	for _, n := range nodes {
+		result = append(result, n.View().Content)
+		if !n.State().Is(tree.NodeCollapsed) {
+			for _, c := range views(n.Children()) {
+				result = append(result, "  "+c)
+			}
+		}
+	}
+	return result
+}
+
+func expand(n tree.Node) {
+	n.Update(n.State() &^ tree.NodeCollapsed)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		opts    []Option
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "object",
+			data: doc,
+			want: []string{
+				"$",
+				`  name: "tree"`,
+				"  version: 1.5",
+				"  tags: [" + tree.Ellipsis + "] 2 items",
+				"  deps: {}",
+				"  meta: {" + tree.Ellipsis + "} 2 keys",
+			},
+		},
+		{
+			name: "expand depth",
+			data: doc,
+			opts: []Option{ExpandDepth(2)},
+			want: []string{
+				"$",
+				`  name: "tree"`,
+				"  version: 1.5",
+				"  tags",
+				`    0: "go"`,
+				`    1: "tui"`,
+				"  deps: {}",
+				"  meta",
+				`    "a b": true`,
+				"    empty: null",
+			},
+		},
+		{
+			name: "collapsed root",
+			data: `[1]`,
+			opts: []Option{ExpandDepth(0)},
+			want: []string{"$: [" + tree.Ellipsis + "] 1 item"},
+		},
+		{
+			name: "scalar",
+			data: `"text"`,
+			want: []string{`$: "text"`},
+		},
+		{
+			name:    "invalid",
+			data:    `{"a": }`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			data:    `{} {}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Parse([]byte(tt.data), append([]Option{plain}, tt.opts...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, views(tree.Nodes{n})); diff != "" {
+				t.Errorf("Parse() views mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFromValue(t *testing.T) {
+	v := struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}{ID: 3, Tags: []string{"x"}}
+	n, err := FromValue(v, plain, ExpandDepth(2))
+	if err != nil {
+		t.Fatalf("FromValue() error = %v", err)
+	}
+	want := []string{"$", "  id: 3", "  tags", `    0: "x"`}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("FromValue() views mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNode_Path(t *testing.T) {
+	n, err := Parse([]byte(`{"a": {"b": [0, 1, 2, {"c d": 1}]}}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	a := n.children[0]
+	b := a.children[0]
+	tests := []struct {
+		name string
+		node *Node
+		want string
+	}{
+		{name: "root", node: n, want: "$"},
+		{name: "member", node: a, want: "$.a"},
+		{name: "nested", node: b, want: "$.a.b"},
+		{name: "element", node: b.children[2], want: "$.a.b[2]"},
+		{name: "quoted", node: b.children[3].children[0], want: `$.a.b[3]["c d"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.Path(); got != tt.want {
+				t.Errorf("Path() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyPath(t *testing.T) {
+	n, _ := Parse([]byte(`{"a": 1}`))
+	if CopyPath(n.children[0]) == nil {
+		t.Errorf("CopyPath() = nil, want a command")
+	}
+	if CopyPath(nil) != nil {
+		t.Errorf("CopyPath(nil) != nil")
+	}
+}
+
+func TestDecode_stream(t *testing.T) {
+	n, err := Decode(strings.NewReader(`[1, 2, 3, 4, 5]`), plain, WithPageSize(2))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	more := tree.Ellipsis + " load more"
+	want := []string{"$", "  0: 1", "  1: 2", "  " + more}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("Decode() views mismatch (-want +got):\n%s", diff)
+	}
+
+	n.Update(n.State() | tree.NodeCollapsed)
+	if got, want := n.View().Content, "$: ["+tree.Ellipsis+"] 2+ items"; got != want {
+		t.Errorf("collapsed View() = %q, want %q", got, want)
+	}
+	expand(n)
+
+	last := n.Children()[len(n.Children())-1]
+	expand(last)
+	if !last.State().Is(tree.NodeCollapsed) {
+		t.Errorf("the load more node should stay collapsed")
+	}
+	want = []string{"$", "  0: 1", "  1: 2", "  2: 3", "  3: 4", "  " + more}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("second page views mismatch (-want +got):\n%s", diff)
+	}
+
+	expand(n.Children()[len(n.Children())-1])
+	want = []string{"$", "  0: 1", "  1: 2", "  2: 3", "  3: 4", "  4: 5"}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("last page views mismatch (-want +got):\n%s", diff)
+	}
+	if n.Err() != nil {
+		t.Errorf("Err() = %v", n.Err())
+	}
+}
+
+// selected returns the nodes in the tree of n with the NodeSelected state.
+func selected(n tree.Node) tree.Nodes {
+	var result tree.Nodes
+	if n.State().Is(tree.NodeSelected) {
+		result = append(result, n)
+	}
+	for _, c := range n.Children() {
+		result = append(result, selected(c)...)
+	}
+	return result
+}
+
+func TestDecode_streamSelection(t *testing.T) {
+	n, err := Decode(strings.NewReader(`[1, 2, 3, 4, 5]`), plain, WithPageSize(2), ExpandDepth(1))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	m := tree.New(tree.Nodes{n})
+	m.SetWidth(40)
+	m.SetHeight(10)
+	m.Init()
+	// $, 0, 1, load more
+	m.SetCursor(3)
+
+	cmd := m.ToggleExpand()
+	if got := m.CurrentNode(); got != n.children[2] {
+		t.Errorf("CurrentNode() after loading = %q, want the first loaded element", got.View().Content)
+	}
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(tree.ChangedMsg); ok {
+			if len(msg.Added) != 2 {
+				t.Errorf("ChangedMsg.Added = %d nodes, want 2", len(msg.Added))
+			}
+			m.Update(msg)
+		}
+	}
+	m.MoveDown(1)
+	if got := selected(n); len(got) != 1 || got[0] != n.children[3] {
+		t.Errorf("selected nodes = %d, want only the element after the first loaded one", len(got))
+	}
+}
+
+func TestDecode_streamError(t *testing.T) {
+	n, err := Decode(strings.NewReader(`[1, 2, }`), plain, WithPageSize(5))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if n.Err() == nil {
+		t.Errorf("Err() = nil, want an error")
+	}
+	want := []string{"$", "  0: 1", "  1: 2", "  invalid character ',' looking for beginning of value"}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("Decode() views mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDecode_collapsedRoot(t *testing.T) {
+	n, err := Decode(strings.NewReader(`[1, 2, 3]`), plain, WithPageSize(2), ExpandDepth(0))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if n.Len() != 0 {
+		t.Errorf("Len() = %d before expanding, want 0", n.Len())
+	}
+	_, cmd := n.Update(n.State() &^ tree.NodeCollapsed)
+	want := []string{"$", "  0: 1", "  1: 2", "  " + tree.Ellipsis + " load more"}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("expanded views mismatch (-want +got):\n%s", diff)
+	}
+	if cmd == nil {
+		t.Fatalf("Update() returned no command")
+	}
+	if msg, ok := cmd().(tree.ChangedMsg); !ok || len(msg.Added) != 2 {
+		t.Errorf("Update() command = %#v, want a ChangedMsg with 2 added nodes", cmd())
+	}
+}
+
+func TestDecode_nested(t *testing.T) {
+	n, err := Decode(strings.NewReader(`{"data": [1, 2, 3, 4, 5], "meta": {"count": 5}}`), plain, WithPageSize(2))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	more := tree.Ellipsis + " load more"
+	want := []string{"$", "  data: [" + tree.Ellipsis + "] 2+ items", "  " + more}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("Decode() views mismatch (-want +got):\n%s", diff)
+	}
+
+	data := n.children[0]
+	expand(data)
+	// the members after data are read only when the root loads its next page
+	expand(n.Children()[1])
+	want = []string{
+		"$",
+		"  data",
+		"    0: 1",
+		"    1: 2",
+		"    " + more,
+		"  meta: {" + tree.Ellipsis + "} 1 key",
+	}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("root next page views mismatch (-want +got):\n%s", diff)
+	}
+
+	expand(data.Children()[2])
+	expand(data.Children()[4])
+	want = []string{"data", "  0: 1", "  1: 2", "  2: 3", "  3: 4", "  4: 5"}
+	if diff := cmp.Diff(want, views(tree.Nodes{data})); diff != "" {
+		t.Errorf("data pages views mismatch (-want +got):\n%s", diff)
+	}
+	if n.Err() != nil || data.Err() != nil {
+		t.Errorf("Err() = %v, %v", n.Err(), data.Err())
+	}
+}
+
+func TestDecode_nestedLazy(t *testing.T) {
+	// the reader fails after the first elements of data, which must not be read by Decode
+	r := io.MultiReader(strings.NewReader(`{"data": [1, 2, 3`), iotest.ErrReader(errors.New("broken pipe")))
+	n, err := Decode(r, plain, WithPageSize(2))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	data := n.children[0]
+	if data.Len() != 2 || data.Err() != nil {
+		t.Errorf("data Len() = %d, Err() = %v, want 2 elements and no error", data.Len(), data.Err())
+	}
+	expand(data)
+	expand(data.Children()[2])
+	if data.Err() == nil || n.Err() == nil {
+		t.Errorf("Err() = %v, %v after reading past the data, want the error of the reader", n.Err(), data.Err())
+	}
+}
+
+func TestDecode_object(t *testing.T) {
+	n, err := Decode(strings.NewReader(`{"a": [1]}`), plain)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := []string{"$", "  a: [" + tree.Ellipsis + "] 1 item"}
+	if diff := cmp.Diff(want, views(tree.Nodes{n})); diff != "" {
+		t.Errorf("Decode() views mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,408 @@
+// Package mdtree provides tree nodes for the outline of a Markdown document, built from its headings.
+//
+// Both the ATX (# Title) and the Setext (Title followed by a line of = or -) headings are
+// recognized, and a heading becomes the child of the closest heading of a lower level
+// before it, making a table of contents of the document:
+//
+//	doc, err := mdtree.ParseFile("README.md", mdtree.Preview(5))
+//	if err != nil {
+//		return err
+//	}
+//	t := tree.New(tree.Nodes{doc})
+//
+// The lines inside fenced and indented code blocks are never considered headings.
+package mdtree
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// Styles contains the style definitions for the nodes.
+type Styles struct {
+	// Marker is used for the # characters marking the level of the headings.
+	Marker  lipgloss.Style
+	Heading lipgloss.Style
+	Preview lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for the nodes.
+func DefaultStyles() Styles {
+	return Styles{
+		Marker:  lipgloss.NewStyle().Faint(true),
+		Heading: lipgloss.NewStyle().Bold(true),
+		Preview: lipgloss.NewStyle().Faint(true),
+	}
+}
+
+type config struct {
+	name    string
+	styles  Styles
+	preview int
+}
+
+// Option configures how the nodes are built from the Markdown document.
+type Option func(*config)
+
+// WithName sets the name shown for the document node.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithStyles sets the Styles used for rendering the nodes.
+func WithStyles(s Styles) Option {
+	return func(c *config) {
+		c.styles = s
+	}
+}
+
+// Preview adds to every heading a multi line child node showing the beginning of its section,
+// up to the first subheading, limited to maxLines lines.
+func Preview(maxLines int) Option {
+	return func(c *config) {
+		c.preview = max(0, maxLines)
+	}
+}
+
+// Node is a tree.Node for a Markdown document, one of its headings, or the preview of a section.
+type Node struct {
+	cfg *config
+
+	parent *Node
+	// level is the level of the heading, 0 for the document and -1 for a preview.
+	level int
+	text  string
+	// start and end are the first and last lines of the section, starting from 1.
+	start, end int
+	children   []*Node
+	state      tree.NodeState
+}
+
+// ParseFile reads the Markdown document at path, and returns its node.
+func ParseFile(path string, opts ...Option) (*Node, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(src, append([]Option{WithName(filepath.Base(path))}, opts...)...), nil
+}
+
+// Parse returns the node for the Markdown document src, whose children are its top level headings.
+func Parse(src []byte, opts ...Option) *Node {
+	cfg := config{styles: DefaultStyles()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+	lines := strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+
+	doc := &Node{cfg: &cfg, text: cfg.name, start: 1, end: len(lines)}
+	headings := scan(lines)
+	// stack holds the current heading of every level, the document being the first.
+	stack := []*Node{doc}
+	for i, h := range headings {
+		for stack[len(stack)-1].level >= h.level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		n := &Node{cfg: &cfg, parent: parent, level: h.level, text: h.text, start: h.start + 1, end: len(lines)}
+		for _, next := range headings[i+1:] {
+			if next.level <= h.level {
+				n.end = next.start
+				break
+			}
+		}
+		if cfg.preview > 0 {
+			bodyEnd := len(lines)
+			if i+1 < len(headings) {
+				bodyEnd = headings[i+1].start
+			}
+			n.addPreview(lines[h.body:bodyEnd])
+		}
+		parent.children = append(parent.children, n)
+		stack = append(stack, n)
+	}
+	doc.setCollapsible()
+	return doc
+}
+
+// addPreview adds the preview child for the body of the section.
+func (n *Node) addPreview(body []string) {
+	for len(body) > 0 && isBlank(body[0]) {
+		body = body[1:]
+	}
+	for len(body) > 0 && isBlank(body[len(body)-1]) {
+		body = body[:len(body)-1]
+	}
+	if len(body) == 0 {
+		return
+	}
+	if len(body) > n.cfg.preview {
+		body = append(body[:n.cfg.preview:n.cfg.preview], tree.Ellipsis)
+	}
+	p := &Node{cfg: n.cfg, parent: n, level: -1, text: strings.Join(body, "\n"), start: n.start, end: n.end}
+	p.state = tree.NodeIsMultiLine
+	n.children = append(n.children, p)
+}
+
+func (n *Node) setCollapsible() {
+	if len(n.children) > 0 {
+		n.state |= tree.NodeCollapsible
+	}
+	for _, c := range n.children {
+		c.setCollapsible()
+	}
+}
+
+// heading is a heading found in the document. The line numbers start from 0.
+type heading struct {
+	level int
+	text  string
+	// start is the first line of the heading, and body the first line after it.
+	start, body int
+}
+
+// scan finds the headings of the document, skipping the code blocks.
+func scan(lines []string) []heading {
+	headings := make([]heading, 0)
+	// fence is the opening sequence of the current fenced code block.
+	fence := ""
+	// para is the first line of the current paragraph, or -1.
+	para := -1
+	for i, l := range lines {
+		if fence != "" {
+			if closesFence(l, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if isBlank(l) {
+			para = -1
+			continue
+		}
+		if indentation(l) >= 4 {
+			// An indented code block, or the continuation of a paragraph.
+			continue
+		}
+		t := strings.TrimLeft(l, " \t")
+		if f := openingFence(t); f != "" {
+			fence = f
+			para = -1
+			continue
+		}
+		if level, text, ok := atxHeading(t); ok {
+			headings = append(headings, heading{level: level, text: text, start: i, body: i + 1})
+			para = -1
+			continue
+		}
+		if para >= 0 {
+			if level := setextUnderline(t); level > 0 {
+				text := make([]string, 0, i-para)
+				for _, pl := range lines[para:i] {
+					text = append(text, strings.TrimSpace(pl))
+				}
+				headings = append(headings, heading{level: level, text: strings.Join(text, " "), start: para, body: i + 1})
+				para = -1
+				continue
+			}
+			if startsBlock(t) {
+				para = -1
+			}
+			continue
+		}
+		if startsBlock(t) {
+			continue
+		}
+		para = i
+	}
+	return headings
+}
+
+// indentation returns the number of columns of leading white space of line l.
+func indentation(l string) int {
+	col := 0
+	for _, r := range l {
+		switch r {
+		case ' ':
+			col++
+		case '\t':
+			col += 4 - col%4
+		default:
+			return col
+		}
+	}
+	return col
+}
+
+func isBlank(l string) bool {
+	return strings.TrimSpace(l) == ""
+}
+
+// openingFence returns the fence opening a code block on line l, which is already unindented.
+func openingFence(l string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(l) - len(strings.TrimLeft(l, c))
+		if n < 3 {
+			continue
+		}
+		// The info string of a backtick fence can't contain backticks.
+		if c == "`" && strings.Contains(l[n:], "`") {
+			return ""
+		}
+		return l[:n]
+	}
+	return ""
+}
+
+// closesFence checks if line l closes the code block opened with fence.
+func closesFence(l string, fence string) bool {
+	if indentation(l) >= 4 {
+		return false
+	}
+	t := strings.TrimSpace(l)
+	n := len(t) - len(strings.TrimLeft(t, fence[:1]))
+	return n >= len(fence) && n == len(t)
+}
+
+// atxHeading parses the ATX heading on line l, which is already unindented.
+func atxHeading(l string) (int, string, bool) {
+	level := len(l) - len(strings.TrimLeft(l, "#"))
+	if level < 1 || level > 6 {
+		return 0, "", false
+	}
+	rest := l[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+	text := strings.TrimSpace(rest)
+	// The optional closing sequence of # characters must be preceded by a space.
+	if closing := strings.TrimRight(text, "#"); closing == "" {
+		text = ""
+	} else if len(closing) < len(text) && (strings.HasSuffix(closing, " ") || strings.HasSuffix(closing, "\t")) {
+		text = strings.TrimSpace(closing)
+	}
+	return level, text, true
+}
+
+// setextUnderline returns the level of the heading underlined by line l, or 0 if l is not an underline.
+func setextUnderline(l string) int {
+	t := strings.TrimRight(l, " \t")
+	switch {
+	case t == "":
+		return 0
+	case strings.Trim(t, "=") == "":
+		return 1
+	case strings.Trim(t, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+// startsBlock checks if line l, which is already unindented, starts a block which can't
+// be the text of a Setext heading, like a block quote, a list item or a thematic break.
+func startsBlock(l string) bool {
+	switch {
+	case strings.HasPrefix(l, ">"):
+		return true
+	case isThematicBreak(l):
+		return true
+	case len(l) > 1 && strings.ContainsRune("-*+", rune(l[0])) && (l[1] == ' ' || l[1] == '\t'):
+		return true
+	}
+	digits := len(l) - len(strings.TrimLeft(l, "0123456789"))
+	return digits > 0 && digits < 10 && len(l) > digits+1 &&
+		(l[digits] == '.' || l[digits] == ')') && (l[digits+1] == ' ' || l[digits+1] == '\t')
+}
+
+func isThematicBreak(l string) bool {
+	t := strings.NewReplacer(" ", "", "\t", "").Replace(l)
+	if len(t) < 3 {
+		return false
+	}
+	return strings.Trim(t, t[:1]) == "" && strings.ContainsRune("-*_", rune(t[0]))
+}
+
+// Level returns the level of the heading, from 1 to 6. It is 0 for the document node.
+func (n *Node) Level() int {
+	return max(0, n.level)
+}
+
+// Text returns the text of the heading, or the name of the document.
+func (n *Node) Text() string {
+	return n.text
+}
+
+// Title returns the text of the heading, to be used by the tree.Breadcrumb.
+func (n *Node) Title() string {
+	if n.IsPreview() {
+		return ""
+	}
+	return n.text
+}
+
+// Lines returns the range of lines of the section, starting from 1. The range includes
+// the heading, and the subsections.
+func (n *Node) Lines() (start, end int) {
+	return n.start, n.end
+}
+
+// IsPreview reports whether the node is the preview of the section of its parent.
+func (n *Node) IsPreview() bool {
+	return n.level < 0
+}
+
+func (n *Node) View() tea.View {
+	s := n.cfg.styles
+	switch {
+	case n.IsPreview():
+		return tea.NewView(s.Preview.Render(n.text))
+	case n.level == 0:
+		return tea.NewView(s.Heading.Render(n.text))
+	}
+	return tea.NewView(s.Marker.Render(strings.Repeat("#", n.level)) + " " + s.Heading.Render(n.text))
+}
+
+func (n *Node) Parent() tree.Node {
+	if n == nil || n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Init() tea.Cmd {
+	return nil
+}
+
+func (n *Node) Children() tree.Nodes {
+	if len(n.children) == 0 {
+		return nil
+	}
+	nodes := make(tree.Nodes, len(n.children))
+	for i, c := range n.children {
+		nodes[i] = c
+	}
+	return nodes
+}
+
+func (n *Node) State() tree.NodeState {
+	return n.state
+}
+
+func (n *Node) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tree.NodeState:
+		n.state = m
+		if n.IsPreview() {
+			n.state |= tree.NodeIsMultiLine
+		}
+	}
+	return n, nil
+}
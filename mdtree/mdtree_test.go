@@ -0,0 +1,154 @@
+package mdtree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+const doc = `# Title
+
+Introduction.
+
+## Install ##
+
+    # indented code, not a heading
+
+` + "```sh" + `
+# a comment, not a heading
+` + "```" + `
+
+## Usage
+### Flags
+Details
+-------
+
+Body
+of the section.
+
+Other
+=====
+
+#hashtag, not a heading
+- item
+---
+> quote
+===
+`
+
+// plain renders the nodes without styles.
+var plain = WithStyles(Styles{})
+
+// outline returns the views and line ranges of the nodes, with the children indented.
+func outline(nodes tree.Nodes) []string {
+	result := make([]string, 0)
+	for _, n := range nodes {
+		mn := n.(*Node)
+		start, end := mn.Lines()
+		result = append(result, fmt.Sprintf("%s %d-%d", strings.ReplaceAll(n.View().Content, "\n", "|"), start, end))
+		for _, c := range outline(n.Children()) {
+			result = append(result, "  "+c)
+		}
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "outline",
+			src:  doc,
+			opts: []Option{WithName("doc.md")},
+			want: []string{
+				"doc.md 1-28",
+				"  # Title 1-20",
+				"    ## Install 5-12",
+				"    ## Usage 13-14",
+				"      ### Flags 14-14",
+				"    ## Details 15-20",
+				"  # Other 21-28",
+			},
+		},
+		{
+			name: "preview",
+			src:  doc,
+			opts: []Option{Preview(1)},
+			want: []string{
+				" 1-28",
+				"  # Title 1-20",
+				"    Introduction. 1-20",
+				"    ## Install 5-12",
+				"          # indented code, not a heading|" + tree.Ellipsis + " 5-12",
+				"    ## Usage 13-14",
+				"      ### Flags 14-14",
+				"    ## Details 15-20",
+				"      Body|" + tree.Ellipsis + " 15-20",
+				"  # Other 21-28",
+				"    #hashtag, not a heading|" + tree.Ellipsis + " 21-28",
+			},
+		},
+		{
+			name: "unclosed fence",
+			src:  "# A\n~~~~\n# B\n~~~\n## C\n",
+			want: []string{" 1-5", "  # A 1-5"},
+		},
+		{
+			name: "closing sequence",
+			src:  "## A # B ##\n###### C#\n####### D\n#\n",
+			want: []string{" 1-4", "  ## A # B 1-3", "    ###### C# 2-3", "  #  4-4"},
+		},
+		{
+			name: "crlf",
+			src:  "A\r\n=\r\nb\r\n",
+			want: []string{" 1-3", "  # A 1-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := Parse([]byte(tt.src), append([]Option{plain}, tt.opts...)...)
+			if diff := cmp.Diff(tt.want, outline(tree.Nodes{n})); diff != "" {
+				t.Errorf("Parse() outline mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNode_Update_preview(t *testing.T) {
+	n := Parse([]byte("# A\ntext\n"), Preview(3))
+	p := n.children[0].children[0]
+	if !p.IsPreview() {
+		t.Fatalf("IsPreview() = false")
+	}
+	p.Update(tree.NodeSelected)
+	if !p.State().Is(tree.NodeIsMultiLine) {
+		t.Errorf("the preview lost the NodeIsMultiLine state")
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(path, []byte("Title\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	n, err := ParseFile(path, plain)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	want := []string{"README.md 1-2", "  ## Title 1-2"}
+	if diff := cmp.Diff(want, outline(tree.Nodes{n})); diff != "" {
+		t.Errorf("ParseFile() outline mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := ParseFile(filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Errorf("ParseFile() of a missing file should fail")
+	}
+}
@@ -26,6 +26,12 @@ type Node interface {
 	State() NodeState
 }
 
+// Marker is implemented by the nodes which can be marked by the application, like the files
+// selected for a copy. The marked nodes are rendered with the Marked style, and counted by the status line.
+type Marker interface {
+	Marked() bool
+}
+
 // Nodes is a slice of Node elements, usually representing the children of a Node.
 type Nodes []Node
 
@@ -50,6 +56,8 @@ const (
 	// nodeSkipRender shows if the node will not be rendered
 	// NOTE(marius): this might overlap with NodeHidden
 	nodeSkipRender
+	// NodeLoading hints that the children of the node are being loaded
+	NodeLoading
 
 	// NodeMaxState serves no other purpose than as a sentinel value for outside Node interface
 	// implementations to append their own states.
@@ -102,6 +110,45 @@ func (n Nodes) sequentialNodes() Nodes {
 	return seq
 }
 
+// indexOf returns the position of nn in the slice, or -1 if it's not present.
+func (n Nodes) indexOf(nn Node) int {
+	for i, p := range n {
+		if p == nn {
+			return i
+		}
+	}
+	return -1
+}
+
+// all returns the non-hidden nodes of the slice and all their descendants,
+// regardless of their collapsed state.
+func (n Nodes) all() Nodes {
+	result := make(Nodes, 0)
+	for _, nn := range n {
+		if nn == nil || isHidden(nn) {
+			continue
+		}
+		result = append(result, nn)
+		result = append(result, nn.Children().all()...)
+	}
+	return result
+}
+
+// marked returns the marked nodes of the slice and of all their descendants.
+func (n Nodes) marked() Nodes {
+	result := make(Nodes, 0)
+	for _, nn := range n {
+		if nn == nil {
+			continue
+		}
+		if isMarked(nn) {
+			result = append(result, nn)
+		}
+		result = append(result, nn.Children().marked()...)
+	}
+	return result
+}
+
 func (n Nodes) UpdateAll(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, 0)
 	for i, nn := range n {
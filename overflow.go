@@ -0,0 +1,124 @@
+package tree
+
+import (
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Overflow describes how the content of a node that is wider than the space
+// left after the tree symbols is displayed.
+type Overflow int
+
+const (
+	// OverflowTruncate cuts the content at the right edge of the viewport and appends an Ellipsis.
+	OverflowTruncate Overflow = iota
+	// OverflowScroll keeps the content on a single line and allows shifting it horizontally,
+	// while the tree symbols stay in place.
+	OverflowScroll
+	// OverflowWrap reflows the content of single line nodes on as many lines as needed
+	// to fit the viewport. The wrapped lines still count as a single row for the cursor.
+	OverflowWrap
+)
+
+const (
+	// ClipLeft marks that the content of a row continues to the left of the visible area.
+	ClipLeft = "«"
+	// ClipRight marks that the content of a row continues to the right of the visible area.
+	ClipRight = "»"
+)
+
+const horizontalStep = 4
+
+// wrapBreakpoints are the characters, besides whitespace, where wrapped content can be broken.
+const wrapBreakpoints = "/.,;:_"
+
+// XOffset returns the horizontal scroll position of the node content.
+func (m *Model) XOffset() int {
+	return m.xOffset
+}
+
+// SetXOffset sets the horizontal scroll position of the node content.
+// It can not go below 0 or past the end of the widest row.
+func (m *Model) SetXOffset(n int) {
+	m.xOffset = clamp(n, 0, m.maxXOffset())
+}
+
+// ScrollLeft shifts the node content n columns to the left.
+// It has no effect unless the Overflow is OverflowScroll.
+func (m *Model) ScrollLeft(n int) {
+	if m.Overflow != OverflowScroll {
+		return
+	}
+	m.SetXOffset(m.xOffset - n)
+}
+
+// ScrollRight shifts the node content n columns to the right.
+// It has no effect unless the Overflow is OverflowScroll.
+func (m *Model) ScrollRight(n int) {
+	if m.Overflow != OverflowScroll {
+		return
+	}
+	m.SetXOffset(m.xOffset + n)
+}
+
+// maxXOffset returns the offset at which the widest of the scrollable rows is fully visible.
+func (m *Model) maxXOffset() int {
+	w := 0
+	for _, n := range m.tree.sequentialNodes() {
+		if m.ScrollFollowsCursor && !isSelected(n) {
+			continue
+		}
+		pw := (m.depth(n)+1)*width(m.Symbols) + m.columnsWidth()
+		w = max(w, pw+lipgloss.Width(n.View().Content))
+	}
+	return max(0, w-m.contentWidth()+1)
+}
+
+// rowXOffset returns the horizontal offset that applies to the node t.
+func (m *Model) rowXOffset(t Node) int {
+	if m.ScrollFollowsCursor && !isSelected(t) {
+		return 0
+	}
+	return m.xOffset
+}
+
+// clipLines applies clip to every line of s.
+func clipLines(s string, width, offset int) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = clip(l, width, offset)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clip returns the width cells of s starting at offset, replacing the first and last
+// visible cells with ClipLeft and ClipRight when s continues beyond them.
+func clip(s string, width, offset int) string {
+	w := ansi.StringWidth(s)
+	if width <= 0 || w <= width {
+		return s
+	}
+	offset = clamp(offset, 0, w-width)
+	if width < 3 {
+		return ansi.Cut(s, offset, offset+width)
+	}
+
+	left, right := offset, offset+width
+	prefix, suffix := "", ""
+	if left > 0 {
+		prefix = ClipLeft
+		left++
+	}
+	if right < w {
+		suffix = ClipRight
+		right--
+	}
+	return prefix + ansi.Cut(s, left, right) + suffix
+}
+
+// wrap reflows s to width cells, breaking words only when they don't fit on a line by themselves.
+func wrap(s string, width int) string {
+	return ansi.Wrap(s, width, wrapBreakpoints)
+}
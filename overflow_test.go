@@ -0,0 +1,243 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func Test_clip(t *testing.T) {
+	type args struct {
+		s      string
+		width  int
+		offset int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty",
+			args: args{},
+			want: "",
+		},
+		{
+			name: "fits",
+			args: args{s: "test", width: 10, offset: 0},
+			want: "test",
+		},
+		{
+			name: "fits - offset is ignored",
+			args: args{s: "test", width: 10, offset: 2},
+			want: "test",
+		},
+		{
+			name: "clipped right",
+			args: args{s: "0123456789", width: 5, offset: 0},
+			want: "0123" + ClipRight,
+		},
+		{
+			name: "clipped both sides",
+			args: args{s: "0123456789", width: 5, offset: 2},
+			want: ClipLeft + "345" + ClipRight,
+		},
+		{
+			name: "clipped left",
+			args: args{s: "0123456789", width: 5, offset: 5},
+			want: ClipLeft + "6789",
+		},
+		{
+			name: "offset past the end",
+			args: args{s: "0123456789", width: 5, offset: 100},
+			want: ClipLeft + "6789",
+		},
+		{
+			name: "too narrow for markers",
+			args: args{s: "0123456789", width: 2, offset: 3},
+			want: "34",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clip(tt.args.s, tt.args.width, tt.args.offset); got != tt.want {
+				t.Errorf("clip() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_ScrollRight(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow Overflow
+		node     *n
+		scroll   int
+		want     int
+	}{
+		{
+			name:     "truncate - no scrolling",
+			overflow: OverflowTruncate,
+			node:     tn(strings.Repeat("x", 30), st(NodeLastChild)),
+			scroll:   5,
+			want:     0,
+		},
+		{
+			name:     "scroll - content fits",
+			overflow: OverflowScroll,
+			node:     tn("test", st(NodeLastChild)),
+			scroll:   5,
+			want:     0,
+		},
+		{
+			name:     "scroll",
+			overflow: OverflowScroll,
+			node:     tn(strings.Repeat("x", 30), st(NodeLastChild)),
+			scroll:   5,
+			want:     5,
+		},
+		{
+			name:     "scroll - past the widest row",
+			overflow: OverflowScroll,
+			node:     tn(strings.Repeat("x", 30), st(NodeLastChild)),
+			scroll:   100,
+			want:     14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockModel(tt.node)
+			m.SetWidth(20)
+			m.Overflow = tt.overflow
+			m.ScrollRight(tt.scroll)
+			if got := m.XOffset(); got != tt.want {
+				t.Errorf("XOffset() = %d, want %d", got, tt.want)
+			}
+			m.ScrollLeft(tt.scroll)
+			if got := m.XOffset(); got != 0 {
+				t.Errorf("XOffset() after ScrollLeft() = %d, want %d", got, 0)
+			}
+		})
+	}
+}
+
+func TestModel_renderNode_scroll(t *testing.T) {
+	m := mockModel()
+	node := tn("0123456789abcdefghij", st(NodeLastChild))
+	m.tree = Nodes{node}
+	m.SetWidth(13)
+	m.Overflow = OverflowScroll
+
+	m.ScrollRight(4)
+	got := m.renderNode(node)
+	want := upAndRight + ClipLeft + "56789ab" + ClipRight
+	if got != want {
+		t.Errorf("renderNode() = %q, want %q", got, want)
+	}
+}
+
+func TestModel_renderNode_wrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		node  *n
+		width int
+		want  string
+	}{
+		{
+			name:  "fits",
+			node:  tn("one two", st(NodeLastChild)),
+			width: 20,
+			want:  upAndRight + "one two         ",
+		},
+		{
+			name:  "last child",
+			node:  tn("one two three", st(NodeLastChild)),
+			width: 12,
+			want: upAndRight + "one two \n" +
+				"   three   ",
+		},
+		{
+			name: "with sibling",
+			node: tn("root", st(NodeLastChild), c(
+				tn("one two three"),
+				tn("four"),
+			)),
+			width: 15,
+			want: upAndRight + "root       \n" +
+				"   ├─ one two \n" +
+				"   │  three   \n" +
+				"   └─ four    ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockModel(tt.node)
+			m.SetWidth(tt.width)
+			m.Overflow = OverflowWrap
+
+			got := m.renderNode(tt.node)
+			linesGot := strings.Split(got, "\n")
+			linesWant := strings.Split(tt.want, "\n")
+			if len(linesWant) != len(linesGot) {
+				t.Fatalf("Different line count %d, got %d: %q", len(linesWant), len(linesGot), got)
+			}
+			for i, lw := range linesWant {
+				if lg := linesGot[i]; lw != lg {
+					t.Errorf("%2d %q| %q", i, lw, lg)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_SetCursor_wrap(t *testing.T) {
+	// every child is wrapped on two lines: root 0, one 1-2, two 3-4, three 5-6
+	tree := tn("root", st(NodeLastChild), c(
+		tn("one two three four"),
+		tn("five six seven eight"),
+		tn("nine ten eleven twelve"),
+	))
+	m := mockModel(tree)
+	m.SetWidth(20)
+	m.SetHeight(4)
+	m.Overflow = OverflowWrap
+	m.Init()
+	m.View()
+
+	m.GotoBottom()
+	if got := m.YOffset(); got != 3 {
+		t.Errorf("YOffset() after GotoBottom() = %d, want %d", got, 3)
+	}
+	if view := m.View().Content; !strings.Contains(view, "nine ten") || !strings.Contains(view, "twelve") {
+		t.Errorf("the selected node is not visible after GotoBottom():\n%s", view)
+	}
+	if got := m.ScrollPercent(); got != 1.0 {
+		t.Errorf("ScrollPercent() = %f, want %f", got, 1.0)
+	}
+
+	m.click(tea.Mouse{Y: 0, Button: tea.MouseLeft})
+	if got := m.CurrentNode(); got != tree.c[1] {
+		t.Errorf("click() selected %q, want %q", got.View().Content, tree.c[1].n)
+	}
+	m.GotoTop()
+	if got := m.YOffset(); got != 0 {
+		t.Errorf("YOffset() after GotoTop() = %d, want %d", got, 0)
+	}
+}
+
+func TestModel_renderPrefixForWrappedNode_active(t *testing.T) {
+	tree := tn("root", st(NodeLastChild), c(tn("one two three four"), tn("five")))
+	m := mockModel(tree)
+	m.Styles.ActiveSymbol = markerStyle{}
+	m.cursor = 1
+	m.updateActivePath()
+
+	lines := strings.Split(m.renderPrefixForWrappedNode(tree.c[0], 2), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("renderPrefixForWrappedNode() = %q, want 2 lines", lines)
+	}
+	if !strings.HasSuffix(lines[1], "***") {
+		t.Errorf("the continuation line %q doesn't use the active symbol style", lines[1])
+	}
+}
@@ -0,0 +1,232 @@
+package tree
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// PathOption configures how the nodes are built by FromPaths.
+type PathOption func(*pathConfig)
+
+type pathConfig struct {
+	sep       string
+	compact   bool
+	collapsed bool
+	// root holds the top level nodes as its children.
+	root *PathNode
+}
+
+// CompactChains shows the chains of nodes having a single child as one node,
+// like "a/b/c" for a directory containing only a directory. It can be toggled
+// later with PathNode.SetCompact.
+func CompactChains() PathOption {
+	return func(c *pathConfig) {
+		c.compact = true
+	}
+}
+
+// CollapseParents builds the nodes having children in the collapsed state.
+func CollapseParents() PathOption {
+	return func(c *pathConfig) {
+		c.collapsed = true
+	}
+}
+
+// PathNode is a Node built by FromPaths, for a segment of one or more of the paths.
+type PathNode struct {
+	cfg *pathConfig
+
+	parent   *PathNode
+	name     string
+	path     string
+	leaf     bool
+	children []*PathNode
+	state    NodeState
+}
+
+// FromPaths builds the nodes for a flat list of paths, like the output of "git ls-files" or
+// a list of topics, whose segments are delimited by sep. The nodes for the segments shared
+// by several paths, like the directories, are created as needed, in the order in which the
+// paths first mention them.
+func FromPaths(paths []string, sep string, opts ...PathOption) Nodes {
+	cfg := &pathConfig{sep: sep}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	root := &PathNode{cfg: cfg}
+	cfg.root = root
+	for _, p := range paths {
+		segments := []string{p}
+		if sep != "" {
+			segments = strings.Split(p, sep)
+		}
+		n := root
+		for _, s := range segments {
+			if s == "" {
+				continue
+			}
+			c := n.child(s)
+			if c == nil {
+				c = &PathNode{cfg: cfg, parent: n, name: s, path: s}
+				if n != root {
+					c.path = n.path + sep + s
+				}
+				n.children = append(n.children, c)
+			}
+			n = c
+		}
+		if n != root {
+			n.leaf = true
+		}
+	}
+	nodes := make(Nodes, len(root.children))
+	for i, c := range root.children {
+		c.parent = nil
+		c.setState()
+		nodes[i] = c
+	}
+	return nodes
+}
+
+func (n *PathNode) child(name string) *PathNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *PathNode) setState() {
+	if len(n.children) > 0 {
+		n.state = NodeCollapsible
+		if n.cfg.collapsed {
+			n.state |= NodeCollapsed
+		}
+	}
+	for _, c := range n.children {
+		c.setState()
+	}
+}
+
+// absorbed checks if the node is shown as part of the compacted chain of its parent.
+func (n *PathNode) absorbed() bool {
+	p := n.parent
+	return n.cfg.compact && p != nil && p.chains()
+}
+
+// chains checks if the node is compacted together with its only child.
+func (n *PathNode) chains() bool {
+	return len(n.children) == 1 && !n.leaf
+}
+
+// chainEnd returns the last node of the compacted chain starting at n, or n itself.
+func (n *PathNode) chainEnd() *PathNode {
+	for n.cfg.compact && n.chains() {
+		n = n.children[0]
+	}
+	return n
+}
+
+// SetCompact toggles the compaction of the chains of nodes having a single child,
+// in the whole tree of n. The returned command notifies the tree Model of the change.
+func (n *PathNode) SetCompact(on bool) tea.Cmd {
+	if n.cfg.compact == on {
+		return nil
+	}
+	n.cfg.compact = on
+	// The selection moves to the row showing the selected node.
+	var walk func(c *PathNode)
+	walk = func(c *PathNode) {
+		if c.absorbed() && c.state.Is(NodeSelected) {
+			c.state &^= NodeSelected
+			if p, ok := c.Parent().(*PathNode); ok {
+				p.state |= NodeSelected
+			}
+		}
+		for _, cc := range c.children {
+			walk(cc)
+		}
+	}
+	walk(n.cfg.root)
+	return func() tea.Msg {
+		return ChangedMsg{}
+	}
+}
+
+// Compact reports whether the chains of nodes having a single child are compacted.
+func (n *PathNode) Compact() bool {
+	return n.cfg.compact
+}
+
+// Name returns the segment of the node, or the segments of its compacted chain joined by the separator.
+func (n *PathNode) Name() string {
+	names := []string{n.name}
+	for c := n; c.cfg.compact && c.chains(); {
+		c = c.children[0]
+		names = append(names, c.name)
+	}
+	return strings.Join(names, n.cfg.sep)
+}
+
+// Title returns the Name of the node, to be used by the Breadcrumb.
+func (n *PathNode) Title() string {
+	return n.Name()
+}
+
+// Path returns the segments from the top level node up to n, or up to the end of its
+// compacted chain, joined by the separator.
+func (n *PathNode) Path() string {
+	return n.chainEnd().path
+}
+
+// IsLeaf reports whether the path of the node was in the list, as opposed to being
+// only the prefix of other paths.
+func (n *PathNode) IsLeaf() bool {
+	return n.chainEnd().leaf
+}
+
+func (n *PathNode) Init() tea.Cmd {
+	return nil
+}
+
+func (n *PathNode) View() tea.View {
+	return tea.NewView(n.Name())
+}
+
+// Parent returns the parent of the node, skipping the nodes compacted in the same chain.
+func (n *PathNode) Parent() Node {
+	p := n.parent
+	for p != nil && p.absorbed() {
+		p = p.parent
+	}
+	if p == nil {
+		return nil
+	}
+	return p
+}
+
+func (n *PathNode) Children() Nodes {
+	end := n.chainEnd()
+	if len(end.children) == 0 {
+		return nil
+	}
+	nodes := make(Nodes, len(end.children))
+	for i, c := range end.children {
+		nodes[i] = c
+	}
+	return nodes
+}
+
+func (n *PathNode) State() NodeState {
+	return n.state
+}
+
+func (n *PathNode) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case NodeState:
+		n.state = m
+	}
+	return n, nil
+}
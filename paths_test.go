@@ -0,0 +1,174 @@
+package tree
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testPaths = []string{
+	"cmd/tree/main.go",
+	"internal/pkg/util/strings.go",
+	"README.md",
+	"cmd/tree/flags.go",
+	"docs",
+	"docs/guide/intro.md",
+	"README.md",
+	"/vendor//lib.go",
+}
+
+func printPaths(t *testing.T, nodes Nodes) []string {
+	t.Helper()
+	buf := bytes.Buffer{}
+	if _, err := Fprint(&buf, nodes); err != nil {
+		t.Fatalf("Fprint() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return lines
+}
+
+func TestFromPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		sep   string
+		opts  []PathOption
+		want  []string
+	}{
+		{
+			name:  "empty",
+			paths: nil,
+			sep:   "/",
+			want:  []string{""},
+		},
+		{
+			name:  "paths",
+			paths: testPaths,
+			sep:   "/",
+			want: []string{
+				"├─ cmd",
+				"│  └─ tree",
+				"│     ├─ main.go",
+				"│     └─ flags.go",
+				"├─ internal",
+				"│  └─ pkg",
+				"│     └─ util",
+				"│        └─ strings.go",
+				"├─ README.md",
+				"├─ docs",
+				"│  └─ guide",
+				"│     └─ intro.md",
+				"└─ vendor",
+				"   └─ lib.go",
+			},
+		},
+		{
+			name:  "compact",
+			paths: testPaths,
+			sep:   "/",
+			opts:  []PathOption{CompactChains()},
+			want: []string{
+				"├─ cmd/tree",
+				"│  ├─ main.go",
+				"│  └─ flags.go",
+				"├─ internal/pkg/util/strings.go",
+				"├─ README.md",
+				"├─ docs",
+				"│  └─ guide/intro.md",
+				"└─ vendor/lib.go",
+			},
+		},
+		{
+			name:  "topics",
+			paths: []string{"orders.created", "orders.paid", "users.created"},
+			sep:   ".",
+			opts:  []PathOption{CompactChains(), CollapseParents()},
+			want: []string{
+				"├─ orders",
+				"└─ users.created",
+			},
+		},
+		{
+			name:  "no separator",
+			paths: []string{"a/b", "a/b", "c"},
+			sep:   "",
+			want:  []string{"├─ a/b", "└─ c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := FromPaths(tt.paths, tt.sep, tt.opts...)
+			if diff := cmp.Diff(tt.want, printPaths(t, nodes)); diff != "" {
+				t.Errorf("FromPaths() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPathNode_SetCompact(t *testing.T) {
+	nodes := FromPaths(testPaths, "/")
+	m := New(nodes)
+	m.SetWidth(40)
+	m.SetHeight(20)
+	m.Init()
+	// cmd, tree, main.go, flags.go, internal, pkg
+	m.SetCursor(5)
+
+	cmd := nodes[0].(*PathNode).SetCompact(true)
+	if cmd == nil {
+		t.Fatalf("SetCompact() returned no command")
+	}
+	if _, ok := cmd().(ChangedMsg); !ok {
+		t.Fatalf("SetCompact() command returned %T", cmd())
+	}
+	m.Update(cmd())
+	if got := m.CurrentNode().(*PathNode).Name(); got != "internal/pkg/util/strings.go" {
+		t.Errorf("selection after compacting = %q", got)
+	}
+	if got := m.CurrentNode().(*PathNode).Path(); got != "internal/pkg/util/strings.go" {
+		t.Errorf("Path() = %q", got)
+	}
+	if !m.CurrentNode().(*PathNode).IsLeaf() {
+		t.Errorf("IsLeaf() = false for a compacted chain ending with a path")
+	}
+	if cmd := nodes[0].(*PathNode).SetCompact(true); cmd != nil {
+		t.Errorf("SetCompact() without a change returned a command")
+	}
+
+	intro := nodes[3].Children()[0].(*PathNode)
+	if intro.Name() != "guide/intro.md" || intro.Path() != "docs/guide/intro.md" {
+		t.Errorf("Name() = %q, Path() = %q", intro.Name(), intro.Path())
+	}
+	if p := nodes[0].Children()[0].Parent(); p != nodes[0] {
+		t.Errorf("Parent() of a node after a compacted chain should be the start of the chain")
+	}
+
+	m.Update(nodes[1].(*PathNode).SetCompact(false)())
+	if got := m.CurrentNode().(*PathNode).Name(); got != "internal" {
+		t.Errorf("selection after expanding = %q", got)
+	}
+	want := []string{
+		"├─ cmd",
+		"│  └─ tree",
+		"│     ├─ main.go",
+		"│     └─ flags.go",
+		"├─ internal",
+		"│  └─ pkg",
+		"│     └─ util",
+		"│        └─ strings.go",
+		"├─ README.md",
+		"├─ docs",
+		"│  └─ guide",
+		"│     └─ intro.md",
+		"└─ vendor",
+		"   └─ lib.go",
+	}
+	if diff := cmp.Diff(want, printPaths(t, nodes)); diff != "" {
+		t.Errorf("SetCompact(false) mismatch (-want +got):\n%s", diff)
+	}
+}
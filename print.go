@@ -0,0 +1,89 @@
+package tree
+
+import (
+	"io"
+	"strings"
+
+	"charm.land/bubbles/v2/viewport"
+	"charm.land/lipgloss/v2"
+)
+
+// PrintOption configures how the tree is rendered by RenderAll and Fprint.
+type PrintOption func(*Model)
+
+// WithSymbols sets the Symbols used for drawing the tree.
+func WithSymbols(s Symbols) PrintOption {
+	return func(m *Model) {
+		m.Symbols = s
+	}
+}
+
+// WithStyles sets the Styles used for drawing the tree.
+func WithStyles(s Styles) PrintOption {
+	return func(m *Model) {
+		m.Styles = s
+	}
+}
+
+// WithExpander renders the expander column between the tree symbols and the node content.
+func WithExpander() PrintOption {
+	return func(m *Model) {
+		m.ShowExpander = true
+	}
+}
+
+// WithIcons adds a column with the icons returned by the provider before the node content.
+func WithIcons(icons IconProvider) PrintOption {
+	return func(m *Model) {
+		m.Icons = icons
+	}
+}
+
+// WithColumns adds columns at the left of the tree symbols.
+func WithColumns(columns ...Column) PrintOption {
+	return func(m *Model) {
+		m.Columns = columns
+	}
+}
+
+// ExpandAll renders the children of the collapsed nodes too.
+func ExpandAll() PrintOption {
+	return func(m *Model) {
+		m.expandAll = true
+	}
+}
+
+// RenderAll renders all the nodes of the tree, independently of the viewport size
+// and scroll position. By default, it uses the Symbols and Styles of the Model
+// and the children of collapsed nodes are not rendered.
+func (m *Model) RenderAll(opts ...PrintOption) string {
+	vp := viewport.New()
+	p := *m
+	p.Model = &vp
+	for _, fn := range opts {
+		fn(&p)
+	}
+	p.updateActivePath()
+	nodes := p.tree.sequentialNodes()
+	if p.expandAll {
+		nodes = p.tree.all()
+	}
+	p.updateIconWidth(nodes)
+	p.updateColumnWidths(nodes)
+
+	lines := strings.Split(lipgloss.JoinVertical(lipgloss.Left, p.renderNodes(p.tree)...), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Fprint renders the nodes to w the same way as the tree command does,
+// using the default Symbols and Styles unless configured otherwise through opts.
+// The styling is stripped when w is not a terminal.
+func Fprint(w io.Writer, nodes Nodes, opts ...PrintOption) (int, error) {
+	if len(nodes) == 0 {
+		return 0, nil
+	}
+	return lipgloss.Fprintln(w, New(nodes).RenderAll(opts...))
+}
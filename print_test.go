@@ -0,0 +1,85 @@
+package tree
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+)
+
+func TestFprint(t *testing.T) {
+	trimmed := func(s string) string {
+		lines := strings.Split(s, "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight(l, " ")
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+	collapsed := func() *n {
+		tree := newTreeOne()
+		tree.c[1].c[0].s |= NodeCollapsed
+		return tree
+	}
+	colored := DefaultStyles()
+	colored.Symbol = Style(lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0000")))
+	colored.Line = lipgloss.NewStyle().Bold(true)
+
+	tests := []struct {
+		name  string
+		nodes Nodes
+		opts  []PrintOption
+		want  string
+	}{
+		{
+			name:  "empty",
+			nodes: nil,
+			want:  "",
+		},
+		{
+			name:  "treeOne",
+			nodes: Nodes{newTreeOne()},
+			want:  trimmed(treeOneRendered),
+		},
+		{
+			name:  "treeOne - styles are stripped",
+			nodes: Nodes{newTreeOne()},
+			opts:  []PrintOption{WithStyles(colored)},
+			want:  trimmed(treeOneRendered),
+		},
+		{
+			name:  "treeOne - collapsed",
+			nodes: Nodes{collapsed()},
+			want: "└─ tmp\n" +
+				"   ├─ example1\n" +
+				"   └─ test\n" +
+				"      ├─ example\n" +
+				"      ├─ file1\n" +
+				"      ├─ file3\n" +
+				"      └─ file5\n",
+		},
+		{
+			name:  "treeOne - collapsed, expand all",
+			nodes: Nodes{collapsed()},
+			opts:  []PrintOption{ExpandAll()},
+			want:  trimmed(treeOneRendered),
+		},
+		{
+			name:  "edge symbols",
+			nodes: Nodes{tn("one", st(NodeLastChild), c(tn("two")))},
+			opts:  []PrintOption{WithSymbols(NormalEdgeSymbols())},
+			want:  "╵ one\n  ╵ two\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			if _, err := Fprint(&buf, tt.nodes, tt.opts...); err != nil {
+				t.Fatalf("Fprint() error = %s", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Fprint() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package proctree
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Process holds the information about a process, read from its /proc/<pid>/stat file.
+type Process struct {
+	PID  int
+	PPID int
+	// Command is the file name of the executable, truncated by the kernel to 15 characters.
+	Command string
+	// State is one of the process state characters, like R for running or S for sleeping.
+	State byte
+	// UTime and STime are the times spent by the process in user and kernel mode, in clock ticks.
+	UTime, STime uint64
+	// StartTime is the time the process started after the system boot, in clock ticks.
+	StartTime uint64
+	// RSS is the resident set size of the process, in bytes.
+	RSS int64
+}
+
+// parseStat parses the contents of a /proc/<pid>/stat file.
+func parseStat(data string, pageSize int64) (Process, error) {
+	p := Process{}
+	// The command is between parentheses, and can contain both spaces and parentheses.
+	open, closed := strings.IndexByte(data, '('), strings.LastIndexByte(data, ')')
+	if open < 0 || closed < open {
+		return p, fmt.Errorf("invalid stat format")
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(data[:open]))
+	if err != nil {
+		return p, fmt.Errorf("invalid pid: %w", err)
+	}
+	p.PID = pid
+	p.Command = data[open+1 : closed]
+
+	// The fields after the command, starting with the third one, the state.
+	fields := strings.Fields(data[closed+1:])
+	if len(fields) < 22 {
+		return p, fmt.Errorf("invalid stat format: %d fields", len(fields)+2)
+	}
+	p.State = fields[0][0]
+	values := make([]uint64, len(fields))
+	for _, i := range []int{1, 11, 12, 19, 21} {
+		if values[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return p, fmt.Errorf("invalid field %d: %w", i+3, err)
+		}
+	}
+	p.PPID = int(values[1])
+	p.UTime, p.STime = values[11], values[12]
+	p.StartTime = values[19]
+	p.RSS = int64(values[21]) * pageSize
+	return p, nil
+}
+
+// snapshot holds the processes of the system, read at the same time.
+type snapshot struct {
+	procs []Process
+	// total is the time spent by all the CPUs, in clock ticks, and cpus their number.
+	total uint64
+	cpus  int
+	err   error
+}
+
+// readProc reads the processes from fsys, which is the /proc directory. It only accesses the
+// file system, so it's safe to call it outside the goroutine updating the nodes.
+func readProc(fsys fs.FS, pageSize int64) snapshot {
+	s := snapshot{}
+	entries, err := fs.ReadDir(fsys, ".")
+	if s.err = err; err != nil {
+		return s
+	}
+	s.procs = make([]Process, 0, len(entries))
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil || !e.IsDir() {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, e.Name()+"/stat")
+		if err != nil {
+			// The process exited after the directory was read.
+			continue
+		}
+		p, err := parseStat(string(data), pageSize)
+		if err != nil {
+			continue
+		}
+		s.procs = append(s.procs, p)
+	}
+	if data, err := fs.ReadFile(fsys, "stat"); err == nil {
+		s.total, s.cpus = parseCPUTimes(string(data))
+	}
+	return s
+}
+
+// parseCPUTimes returns the total time spent by the CPUs, and their number, from the contents of /proc/stat.
+func parseCPUTimes(data string) (uint64, int) {
+	var total uint64
+	cpus := 0
+	for _, l := range strings.Split(data, "\n") {
+		fields := strings.Fields(l)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if fields[0] != "cpu" {
+			cpus++
+			continue
+		}
+		// The guest times, after the first eight, are already counted in the user times.
+		for _, f := range fields[1:min(len(fields), 9)] {
+			v, _ := strconv.ParseUint(f, 10, 64)
+			total += v
+		}
+	}
+	return total, max(1, cpus)
+}
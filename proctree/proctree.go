@@ -0,0 +1,340 @@
+// Package proctree provides tree nodes for the processes of a Linux system, read from /proc.
+//
+// The processes are shown under their parents, with PID, CPU and memory columns:
+//
+//	procs, err := proctree.New(os.DirFS("/proc"))
+//	if err != nil {
+//		return err
+//	}
+//	t := tree.New(tree.Nodes{procs.Root()})
+//	t.Columns = procs.Columns()
+//
+// The processes are read again periodically by the Tree, which must receive the messages of
+// the Bubble Tea program. The nodes of the processes which are still running are kept, so their
+// expanded state and the cursor of the tree Model don't move.
+package proctree
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	tree "github.com/mariusor/bubbles-tree"
+	"github.com/mariusor/bubbles-tree/internal/size"
+)
+
+// DefaultInterval is the time between two reads of the processes.
+const DefaultInterval = 2 * time.Second
+
+// SelectedMsg is emitted when the node of a process gets selected in the tree.
+type SelectedMsg struct {
+	tree.Node
+	Process Process
+}
+
+type config struct {
+	name     string
+	pageSize int64
+}
+
+// Option configures how the nodes are built from the processes.
+type Option func(*config)
+
+// WithName sets the name shown for the root node, which is "processes" by default.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// Tree holds the nodes of the processes, indexed by their PID.
+type Tree struct {
+	// Interval is the time between two reads of the processes.
+	Interval time.Duration
+
+	fsys  fs.FS
+	cfg   *config
+	root  *Node
+	nodes map[int]*Node
+	// total is the time spent by the CPUs at the last read, in clock ticks.
+	total   uint64
+	cpus    int
+	stopped bool
+}
+
+// New reads the processes from fsys, which is usually os.DirFS("/proc").
+func New(fsys fs.FS, opts ...Option) (*Tree, error) {
+	cfg := config{name: "processes", pageSize: int64(os.Getpagesize())}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	t := &Tree{
+		Interval: DefaultInterval,
+		fsys:     fsys,
+		cfg:      &cfg,
+		nodes:    make(map[int]*Node),
+	}
+	t.root = &Node{cfg: &cfg}
+	if err := t.Load(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Root returns the node containing the processes without a parent, like init and kthreadd.
+func (t *Tree) Root() *Node {
+	return t.root
+}
+
+// Node returns the node of the process with the pid, or nil if there is no such process.
+func (t *Tree) Node(pid int) *Node {
+	return t.nodes[pid]
+}
+
+// Load reads the processes again, updating the nodes in place.
+func (t *Tree) Load() error {
+	s := readProc(t.fsys, t.cfg.pageSize)
+	t.apply(s)
+	return s.err
+}
+
+// refreshMsg asks the Tree to read the processes.
+type refreshMsg struct {
+	t *Tree
+}
+
+// readMsg holds the processes, read in the background.
+type readMsg struct {
+	t *Tree
+	snapshot
+}
+
+// Init starts the periodic refresh.
+func (t *Tree) Init() tea.Cmd {
+	t.stopped = false
+	return t.tick()
+}
+
+// Stop stops the periodic refresh. It can be restarted with Init.
+func (t *Tree) Stop() {
+	t.stopped = true
+}
+
+func (t *Tree) tick() tea.Cmd {
+	return tea.Tick(t.Interval, func(time.Time) tea.Msg {
+		return refreshMsg{t: t}
+	})
+}
+
+// Update handles the refresh messages of the Tree, and ignores everything else.
+// The updated nodes are sent to the tree Model as a tree.ChangedMsg.
+func (t *Tree) Update(msg tea.Msg) tea.Cmd {
+	switch m := msg.(type) {
+	case refreshMsg:
+		if m.t != t || t.stopped {
+			return nil
+		}
+		fsys, pageSize := t.fsys, t.cfg.pageSize
+		return func() tea.Msg {
+			return readMsg{t: t, snapshot: readProc(fsys, pageSize)}
+		}
+	case readMsg:
+		if m.t != t || t.stopped {
+			return nil
+		}
+		if m.err != nil {
+			return t.tick()
+		}
+		return tea.Batch(t.apply(m.snapshot), t.tick())
+	}
+	return nil
+}
+
+// apply updates the nodes with the processes of the snapshot, returning a command
+// emitting the tree.ChangedMsg if anything changed.
+func (t *Tree) apply(s snapshot) tea.Cmd {
+	if s.err != nil {
+		return nil
+	}
+	elapsed := s.total - t.total
+	if t.total == 0 || s.total <= t.total {
+		elapsed = 0
+	}
+	msg := tree.ChangedMsg{}
+	seen := make(map[int]bool, len(s.procs))
+	for _, p := range s.procs {
+		seen[p.PID] = true
+		n, ok := t.nodes[p.PID]
+		// A PID reused by a new process gets a new node.
+		if ok && n.proc.StartTime != p.StartTime {
+			msg.Removed = append(msg.Removed, n)
+			ok = false
+		}
+		if !ok {
+			n = &Node{cfg: t.cfg, proc: p}
+			t.nodes[p.PID] = n
+			msg.Added = append(msg.Added, n)
+			continue
+		}
+		cpu := 0.0
+		if elapsed > 0 {
+			used := (p.UTime + p.STime) - (n.proc.UTime + n.proc.STime)
+			cpu = float64(used) / float64(elapsed) * float64(s.cpus) * 100
+		}
+		if n.proc != p || n.cpu != cpu {
+			msg.Modified = append(msg.Modified, n)
+		}
+		n.proc, n.cpu = p, cpu
+	}
+	for pid, n := range t.nodes {
+		if !seen[pid] {
+			msg.Removed = append(msg.Removed, n)
+			delete(t.nodes, pid)
+			n.parent = nil
+		}
+	}
+	t.total, t.cpus = s.total, s.cpus
+	t.link()
+	if len(msg.Added)+len(msg.Removed)+len(msg.Modified) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// link rebuilds the hierarchy of the processes. The processes whose parent
+// is not known are shown as children of the root node.
+func (t *Tree) link() {
+	t.root.children = t.root.children[:0]
+	for _, n := range t.nodes {
+		n.children = n.children[:0]
+	}
+	for _, n := range t.nodes {
+		parent, ok := t.nodes[n.proc.PPID]
+		if !ok || parent == n {
+			parent = t.root
+		}
+		n.parent = parent
+		parent.children = append(parent.children, n)
+	}
+	t.root.sort()
+}
+
+// sort orders the children of the node by PID, and updates their collapsible state.
+func (n *Node) sort() {
+	slices.SortFunc(n.children, func(a, b *Node) int {
+		return a.proc.PID - b.proc.PID
+	})
+	n.state &^= tree.NodeCollapsible
+	if len(n.children) > 0 {
+		n.state |= tree.NodeCollapsible
+	}
+	for _, c := range n.children {
+		c.sort()
+	}
+}
+
+// Columns returns the PID, CPU and RSS columns, to be set in the tree Model.
+func (t *Tree) Columns() []tree.Column {
+	return []tree.Column{
+		column(func(n *Node) string { return fmt.Sprintf("%7d", n.proc.PID) }),
+		column(func(n *Node) string { return fmt.Sprintf("%5.1f%%", n.cpu) }),
+		column(func(n *Node) string { return fmt.Sprintf("%10s", size.Format(n.proc.RSS)) }),
+	}
+}
+
+// column renders the value of fn for the nodes of the processes, and nothing for the other nodes.
+func column(fn func(*Node) string) tree.Column {
+	return tree.ColumnFunc(func(n tree.Node) string {
+		if pn, ok := n.(*Node); ok && !pn.IsRoot() {
+			return fn(pn)
+		}
+		return ""
+	})
+}
+
+// Node is a tree.Node for a process.
+type Node struct {
+	cfg *config
+
+	parent   *Node
+	proc     Process
+	cpu      float64
+	children []*Node
+	state    tree.NodeState
+}
+
+// IsRoot reports whether the node is the root node, which is not a process.
+func (n *Node) IsRoot() bool {
+	return n.proc.PID == 0
+}
+
+// Process returns the information about the process, as of the last read.
+func (n *Node) Process() Process {
+	return n.proc
+}
+
+// CPU returns the percentage of CPU time used by the process since the previous read,
+// where 100% is one CPU fully used.
+func (n *Node) CPU() float64 {
+	return n.cpu
+}
+
+// Title returns the command of the process, to be used by the tree.Breadcrumb.
+func (n *Node) Title() string {
+	if n.IsRoot() {
+		return n.cfg.name
+	}
+	return n.proc.Command
+}
+
+func (n *Node) View() tea.View {
+	return tea.NewView(n.Title())
+}
+
+func (n *Node) Parent() tree.Node {
+	if n == nil || n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Init() tea.Cmd {
+	return nil
+}
+
+func (n *Node) Children() tree.Nodes {
+	if len(n.children) == 0 {
+		return nil
+	}
+	nodes := make(tree.Nodes, len(n.children))
+	for i, c := range n.children {
+		nodes[i] = c
+	}
+	return nodes
+}
+
+func (n *Node) State() tree.NodeState {
+	return n.state
+}
+
+// Update sets the state of the node, and emits a SelectedMsg when the node of a process gets selected.
+func (n *Node) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tree.NodeState:
+		selected := !n.state.Is(tree.NodeSelected) && m.Is(tree.NodeSelected)
+		n.state = m
+		if selected && !n.IsRoot() {
+			return n, n.selected
+		}
+	}
+	return n, nil
+}
+
+func (n *Node) selected() tea.Msg {
+	return SelectedMsg{Node: n, Process: n.proc}
+}
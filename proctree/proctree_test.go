@@ -0,0 +1,248 @@
+package proctree
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// stat returns the contents of a /proc/<pid>/stat file.
+func stat(pid, ppid int, comm string, utime, start, rss uint64) *fstest.MapFile {
+	s := fmt.Sprintf("%d (%s) S %d %d %d 0 -1 4194560 100 0 0 0 %d 0 0 0 20 0 1 0 %d 1000000 %d 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n",
+		pid, comm, ppid, pid, pid, utime, start, rss)
+	return &fstest.MapFile{Data: []byte(s)}
+}
+
+func cpuStat(total uint64) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(fmt.Sprintf("cpu  %d 0 0 0 0 0 0 0 0 0\ncpu0 0 0 0 0 0 0 0 0 0 0\ncpu1 0 0 0 0 0 0 0 0 0 0\nintr 0\n", total))}
+}
+
+func testProc() fstest.MapFS {
+	return fstest.MapFS{
+		"stat":         cpuStat(1000),
+		"1/stat":       stat(1, 0, "init", 10, 1, 100),
+		"2/stat":       stat(2, 0, "kthreadd", 0, 1, 0),
+		"10/stat":      stat(10, 1, "sshd", 5, 50, 200),
+		"11/stat":      stat(11, 10, "bash (login)", 5, 60, 300),
+		"12/stat":      stat(12, 2, "kworker/0:1", 0, 70, 0),
+		"self":         &fstest.MapFile{Data: []byte("11")},
+		"sys/kernel/x": &fstest.MapFile{},
+	}
+}
+
+// names returns the titles of the expanded nodes, with their children indented.
+func names(nodes tree.Nodes) []string {
+	result := make([]string, 0)
+	for _, n := range nodes {
+		result = append(result, n.(*Node).Title())
+		if !n.State().Is(tree.NodeCollapsed) {
+			for _, c := range names(n.Children()) {
+				result = append(result, "  "+c)
+			}
+		}
+	}
+	return result
+}
+
+func TestParseStat(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Process
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: string(stat(11, 10, "a) (b", 5, 60, 3).Data),
+			want: Process{PID: 11, PPID: 10, Command: "a) (b", State: 'S', UTime: 5, StartTime: 60, RSS: 3 * 4096},
+		},
+		{name: "no command", data: "11 S 10", wantErr: true},
+		{name: "invalid pid", data: "x (a) S 10", wantErr: true},
+		{name: "truncated", data: "11 (a) S 10 11 11", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStat(tt.data, 4096)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseStat() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	procs, err := New(testProc(), WithName("system"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	want := []string{"system", "  init", "    sshd", "      bash (login)", "  kthreadd", "    kworker/0:1"}
+	if diff := cmp.Diff(want, names(tree.Nodes{procs.Root()})); diff != "" {
+		t.Errorf("New() mismatch (-want +got):\n%s", diff)
+	}
+	if procs.Node(10).Process().PPID != 1 {
+		t.Errorf("Node(10) = %v", procs.Node(10).Process())
+	}
+	if procs.Node(99) != nil {
+		t.Errorf("Node(99) should be nil")
+	}
+}
+
+func TestTree_apply(t *testing.T) {
+	fsys := testProc()
+	procs, err := New(fsys)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	sshd := procs.Node(10)
+	sshd.Update(sshd.State() | tree.NodeCollapsed | tree.NodeSelected)
+
+	// bash used 10 ticks out of 200 of the two CPUs, its parent exited and was replaced by a new
+	// process with the same PID, and kworker/0:1 exited.
+	fsys["stat"] = cpuStat(1200)
+	fsys["11/stat"] = stat(11, 1, "bash (login)", 15, 60, 300)
+	fsys["12/stat"] = stat(12, 1, "vim", 0, 80, 400)
+	fsys["13/stat"] = stat(13, 1, "less", 0, 90, 400)
+	delete(fsys, "10/stat")
+	fsys["10/stat"] = stat(10, 1, "sshd", 0, 95, 200)
+
+	cmd := procs.apply(readProc(fsys, 4096))
+	if cmd == nil {
+		t.Fatalf("apply() returned no command")
+	}
+	msg, ok := cmd().(tree.ChangedMsg)
+	if !ok {
+		t.Fatalf("apply() command returned %T", cmd())
+	}
+	if got, want := titles(msg.Added), []string{"sshd", "vim", "less"}; !sameSet(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+	if got, want := titles(msg.Removed), []string{"sshd", "kworker/0:1"}; !sameSet(got, want) {
+		t.Errorf("Removed = %v, want %v", got, want)
+	}
+
+	want := []string{"processes", "  init", "    sshd", "    bash (login)", "    vim", "    less", "  kthreadd"}
+	if diff := cmp.Diff(want, names(tree.Nodes{procs.Root()})); diff != "" {
+		t.Errorf("apply() mismatch (-want +got):\n%s", diff)
+	}
+	if procs.Node(10) == sshd {
+		t.Errorf("the reused PID should get a new node")
+	}
+	bash := procs.Node(11)
+	if got := bash.CPU(); got != 10 {
+		t.Errorf("CPU() = %v, want 10", got)
+	}
+	if procs.Node(2).State().Is(tree.NodeCollapsible) {
+		t.Errorf("kthreadd without children should not be collapsible")
+	}
+}
+
+func titles(nodes tree.Nodes) []string {
+	result := make([]string, len(nodes))
+	for i, n := range nodes {
+		result[i] = n.(*Node).Title()
+	}
+	return result
+}
+
+func sameSet(a, b []string) bool {
+	count := make(map[string]int)
+	for _, s := range a {
+		count[s]++
+	}
+	for _, s := range b {
+		count[s]--
+	}
+	for _, c := range count {
+		if c != 0 {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+func TestTree_Update(t *testing.T) {
+	fsys := testProc()
+	procs, _ := New(fsys)
+	other, _ := New(fsys)
+
+	if cmd := procs.Update(refreshMsg{t: other}); cmd != nil {
+		t.Errorf("Update() handled the message of another Tree")
+	}
+	cmd := procs.Update(refreshMsg{t: procs})
+	if cmd == nil {
+		t.Fatalf("Update() didn't read the processes")
+	}
+	fsys["20/stat"] = stat(20, 1, "top", 0, 100, 10)
+	read, ok := cmd().(readMsg)
+	if !ok {
+		t.Fatalf("the read command returned %T", cmd())
+	}
+	if procs.Node(20) != nil {
+		t.Errorf("the nodes were updated outside Update")
+	}
+	if cmd := procs.Update(read); cmd == nil {
+		t.Errorf("Update() returned no command")
+	}
+	if procs.Node(20) == nil {
+		t.Errorf("Update() didn't add the new process")
+	}
+
+	procs.Stop()
+	if cmd := procs.Update(refreshMsg{t: procs}); cmd != nil {
+		t.Errorf("a stopped Tree should not read the processes")
+	}
+}
+
+func TestTree_Columns(t *testing.T) {
+	procs, _ := New(testProc())
+	procs.cfg.pageSize = 4096
+	procs.Load()
+	cols := procs.Columns()
+	tests := []struct {
+		name string
+		node tree.Node
+		want []string
+	}{
+		{name: "root", node: procs.Root(), want: []string{"", "", ""}},
+		{name: "process", node: procs.Node(10), want: []string{"     10", "  0.0%", " 800.0 KiB"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, len(cols))
+			for i, c := range cols {
+				got[i] = c.Render(tt.node)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Render() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNode_Update_selected(t *testing.T) {
+	procs, _ := New(testProc())
+	n := procs.Node(11)
+	_, cmd := n.Update(n.State() | tree.NodeSelected)
+	if cmd == nil {
+		t.Fatalf("Update() didn't emit a SelectedMsg")
+	}
+	msg, ok := cmd().(SelectedMsg)
+	if !ok || msg.Process.PID != 11 || msg.Node != n {
+		t.Errorf("Update() emitted %#v", msg)
+	}
+	if _, cmd := n.Update(n.State()); cmd != nil {
+		t.Errorf("Update() of a selected node emitted %v", cmd)
+	}
+	if _, cmd := procs.Root().Update(tree.NodeSelected); cmd != nil {
+		t.Errorf("the root node emitted a SelectedMsg")
+	}
+}
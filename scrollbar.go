@@ -0,0 +1,104 @@
+package tree
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// ScrollbarTrack is drawn on the scrollbar rows which are not covered by the thumb.
+	ScrollbarTrack = "│"
+	// ScrollbarThumb is drawn on the scrollbar rows matching the visible part of the tree.
+	ScrollbarThumb = "┃"
+)
+
+// rowsHeight returns the number of viewport lines available for rendering nodes.
+func (m *Model) rowsHeight() int {
+	if m.ShowStatus {
+		return max(0, m.Height()-1)
+	}
+	return m.Height()
+}
+
+// contentWidth returns the number of viewport columns available for rendering nodes.
+func (m *Model) contentWidth() int {
+	if m.ShowScrollbar {
+		return max(0, m.Width()-1)
+	}
+	return m.Width()
+}
+
+// scrollbarThumb returns the first row and the size of the scrollbar thumb.
+// The thumb size relates to the viewport height the same way the viewport height
+// relates to the number of lines of the visible rows in the tree.
+func (m *Model) scrollbarThumb() (int, int) {
+	h := m.rowsHeight()
+	offsets := m.lineOffsets(m.tree.sequentialNodes())
+	total := offsets[len(offsets)-1]
+	if h <= 0 || total <= h {
+		return 0, h
+	}
+	size := clamp(int(math.Round(float64(h*h)/float64(total))), 1, h)
+	start := int(math.Round(m.ScrollPercent() * float64(h-size)))
+	return start, size
+}
+
+// renderScrollbar renders the scrollbar as a slice of one cell wide rows.
+func (m *Model) renderScrollbar() []string {
+	h := m.rowsHeight()
+	start, size := m.scrollbarThumb()
+
+	track := m.Styles.Scrollbar.Render(ScrollbarTrack)
+	thumb := m.Styles.ScrollbarThumb.Render(ScrollbarThumb)
+
+	bar := make([]string, h)
+	for i := range bar {
+		bar[i] = track
+		if i >= start && i < start+size {
+			bar[i] = thumb
+		}
+	}
+	return bar
+}
+
+// overlayScrollbar replaces the last column of the rendered view with the scrollbar.
+func (m *Model) overlayScrollbar(view string) string {
+	if !m.ShowScrollbar || m.Width() <= 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for i, bar := range m.renderScrollbar() {
+		if i >= len(lines) {
+			break
+		}
+		lines[i] = ansi.Truncate(lines[i], m.contentWidth(), "") + bar
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Status returns a short description of the cursor position in the tree.
+func (m *Model) Status() string {
+	total := len(m.tree.sequentialNodes())
+	n := m.currentNode()
+	if n == nil {
+		return fmt.Sprintf("row - of %d, %d marked", total, len(m.tree.marked()))
+	}
+	return fmt.Sprintf("row %d of %d, depth %d, %d marked", m.cursor+1, total, m.depth(n), len(m.tree.marked()))
+}
+
+// overlayStatus replaces the last line of the rendered view with the status line.
+func (m *Model) overlayStatus(view string) string {
+	if !m.ShowStatus || m.Height() <= 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	if len(lines) < m.Height() {
+		return view
+	}
+	status := ansi.Truncate(m.Status(), m.Width(), Ellipsis)
+	lines[m.Height()-1] = m.Styles.Status.Width(m.Width()).Render(status)
+	return strings.Join(lines, "\n")
+}
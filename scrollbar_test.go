@@ -0,0 +1,119 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestModel_scrollbarThumb(t *testing.T) {
+	tests := []struct {
+		name      string
+		height    int
+		yOffset   int
+		status    bool
+		wantStart int
+		wantSize  int
+	}{
+		{
+			name:      "everything visible",
+			height:    12,
+			wantStart: 0,
+			wantSize:  12,
+		},
+		{
+			name:      "top",
+			height:    4,
+			yOffset:   0,
+			wantStart: 0,
+			wantSize:  1,
+		},
+		{
+			name:      "middle",
+			height:    6,
+			yOffset:   3,
+			wantStart: 2,
+			wantSize:  3,
+		},
+		{
+			name:      "bottom",
+			height:    6,
+			yOffset:   5,
+			wantStart: 3,
+			wantSize:  3,
+		},
+		{
+			name:      "bottom with status line",
+			height:    7,
+			yOffset:   5,
+			status:    true,
+			wantStart: 3,
+			wantSize:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockModel(newTreeOne())
+			m.ShowStatus = tt.status
+			m.SetWidth(30)
+			m.SetHeight(tt.height)
+			m.View()
+			m.SetYOffset(tt.yOffset)
+
+			start, size := m.scrollbarThumb()
+			if start != tt.wantStart || size != tt.wantSize {
+				t.Errorf("scrollbarThumb() = %d, %d, want %d, %d", start, size, tt.wantStart, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestModel_Status(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.SetWidth(30)
+	m.SetHeight(12)
+
+	m.cursor = -1
+	if got, want := m.Status(), "row - of 11, 0 marked"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+
+	// example and file2, under the collapsed example
+	tree.c[1].c[0].m = true
+	tree.c[1].c[0].c[0].m = true
+	tree.c[1].c[0].s |= NodeCollapsed
+	m.SetCursor(4)
+	if got, want := m.Status(), "row 5 of 7, depth 2, 2 marked"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+}
+
+func TestModel_View_scrollbarAndStatus(t *testing.T) {
+	m := mockModel(newTreeOne())
+	m.ShowScrollbar = true
+	m.ShowStatus = true
+	m.SetWidth(40)
+	m.SetHeight(5)
+	m.SetCursor(0)
+
+	lines := strings.Split(ansi.Strip(m.View().Content), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("View() line count = %d, want %d", len(lines), 5)
+	}
+	for i, l := range lines {
+		if w := ansi.StringWidth(l); w != 40 {
+			t.Errorf("View() line %d width = %d, want %d: %q", i, w, 40, l)
+		}
+	}
+	wantBar := []string{ScrollbarThumb, ScrollbarTrack, ScrollbarTrack, ScrollbarTrack}
+	for i, want := range wantBar {
+		if got := ansi.Cut(lines[i], 39, 40); got != want {
+			t.Errorf("View() line %d scrollbar = %q, want %q", i, got, want)
+		}
+	}
+	if want := "row 1 of 11, depth 0, 0 marked"; !strings.HasPrefix(lines[4], want) {
+		t.Errorf("View() status line = %q, want prefix %q", lines[4], want)
+	}
+}
@@ -0,0 +1,93 @@
+package tree
+
+import (
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// ancestors returns the chain of parents of the node n, ordered from the root down.
+func ancestors(n Node) Nodes {
+	chain := make(Nodes, 0)
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		chain = append(chain, p)
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// stickyNodes returns the ancestors of the first row that is not covered by the sticky lines,
+// ordered from the root down. When there are more than m.StickyLines of them,
+// the ones closest to the row are kept.
+func (m *Model) stickyNodes() Nodes {
+	if m.StickyLines <= 0 {
+		return nil
+	}
+	visible := m.tree.sequentialNodes()
+	return m.stickyNodesAt(visible, m.lineOffsets(visible), m.YOffset())
+}
+
+// stickyNodesAt returns the sticky nodes for the viewport scrolled to yOffset, using the
+// line offsets of the visible rows computed by lineOffsets.
+func (m *Model) stickyNodesAt(visible Nodes, offsets []int, yOffset int) Nodes {
+	maxLines := min(m.StickyLines, m.rowsHeight()-1)
+	if maxLines <= 0 {
+		return nil
+	}
+
+	var sticky Nodes
+	for {
+		i := nodeAtLine(offsets, yOffset+len(sticky))
+		if i < 0 {
+			break
+		}
+		chain := m.ancestors(visible[i])
+		if len(chain) > maxLines {
+			chain = chain[len(chain)-maxLines:]
+		}
+		if len(chain) <= len(sticky) {
+			sticky = chain
+			break
+		}
+		sticky = chain
+	}
+	return sticky
+}
+
+// GotoSticky moves the selection to the ancestor shown on the sticky line i, counting from 0 at the top.
+func (m *Model) GotoSticky(i int) tea.Cmd {
+	sticky := m.stickyNodes()
+	if i < 0 || i >= len(sticky) {
+		return noop
+	}
+	return m.GotoNode(sticky[i])
+}
+
+// renderSticky renders the first line of every sticky node with its regular tree symbols.
+func (m *Model) renderSticky() []string {
+	sticky := m.stickyNodes()
+	if len(sticky) == 0 {
+		return nil
+	}
+
+	pad := lipgloss.NewStyle().Width(m.contentWidth()).MaxWidth(m.contentWidth()).Render
+	rendered := make([]string, len(sticky))
+	for i, n := range sticky {
+		row, _, _ := strings.Cut(m.renderRow(n), "\n")
+		rendered[i] = pad(row)
+	}
+	return rendered
+}
+
+// overlaySticky replaces the top lines of the rendered view with the sticky lines.
+func (m *Model) overlaySticky(view string) string {
+	sticky := m.renderSticky()
+	if len(sticky) == 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	copy(lines, sticky)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,143 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func names(nn Nodes) []string {
+	result := make([]string, 0, len(nn))
+	for _, n := range nn {
+		result = append(result, n.View().Content)
+	}
+	return result
+}
+
+func TestModel_stickyNodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   int
+		yOffset int
+		want    []string
+	}{
+		{
+			name:    "disabled",
+			lines:   0,
+			yOffset: 4,
+			want:    []string{},
+		},
+		{
+			name:    "top",
+			lines:   3,
+			yOffset: 0,
+			want:    []string{},
+		},
+		{
+			name:    "/tmp/example1",
+			lines:   3,
+			yOffset: 1,
+			want:    []string{"tmp"},
+		},
+		{
+			name:    "/tmp/test/example/file2",
+			lines:   3,
+			yOffset: 4,
+			want:    []string{"test", "example", "lastchild"},
+		},
+		{
+			name:    "/tmp/test/example/file2 - single line",
+			lines:   1,
+			yOffset: 4,
+			want:    []string{"example"},
+		},
+		{
+			name:    "/tmp/test/file1",
+			lines:   3,
+			yOffset: 8,
+			want:    []string{"tmp", "test"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mockModel(newTreeOne())
+			m.SetWidth(30)
+			m.SetHeight(5)
+			m.View()
+
+			m.StickyLines = tt.lines
+			m.SetYOffset(tt.yOffset)
+			got := names(m.stickyNodes())
+			if len(got) != len(tt.want) {
+				t.Fatalf("stickyNodes() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("stickyNodes() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_click(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.SetWidth(30)
+	m.SetHeight(5)
+	m.View()
+
+	m.StickyLines = 3
+	m.SetYOffset(4)
+
+	m.click(tea.Mouse{Y: 1, Button: tea.MouseLeft})
+	if got := m.currentNode(); got != tree.c[1].c[0] {
+		t.Errorf("click() on sticky line selected %v, want %v", got.View().Content, "example")
+	}
+	m.SetYOffset(0)
+	m.click(tea.Mouse{Y: 2, Button: tea.MouseLeft})
+	if got := m.currentNode(); got != tree.c[1] {
+		t.Errorf("click() on row selected %v, want %v", got.View().Content, "test")
+	}
+}
+
+func TestModel_SetCursor_sticky(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.SetWidth(30)
+	m.SetHeight(5)
+	m.Init()
+	m.View()
+
+	m.StickyLines = 3
+	m.SetYOffset(3)
+	// file2 would be covered by the "tmp", "test" and "example" sticky lines
+	m.SetCursor(4)
+	sticky := m.stickyNodes()
+	if got := m.Cursor() - m.YOffset(); got < len(sticky) {
+		t.Errorf("the cursor is on the line %d of the viewport, under %d sticky lines", got, len(sticky))
+	}
+	if view := m.View().Content; !strings.Contains(view, "file2") {
+		t.Errorf("the selected node is not visible:\n%s", view)
+	}
+}
+
+func TestModel_GotoSticky(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.SetWidth(30)
+	m.SetHeight(5)
+	m.View()
+
+	m.StickyLines = 3
+	m.SetYOffset(4)
+
+	m.Update(tea.KeyPressMsg{Code: '2', Text: "2"})
+	if got := m.currentNode(); got != tree.c[1].c[0] {
+		t.Errorf("GotoSticky(1) selected %v, want %v", got.View().Content, "example")
+	}
+	if cmd := m.GotoSticky(3); cmd != nil {
+		t.Errorf("GotoSticky() past the sticky lines returned a command")
+	}
+}
@@ -1,9 +1,12 @@
 package tree
 
 import (
-	"unicode/utf8"
+	"os"
+	"runtime"
+	"strings"
 
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type Symbols struct {
@@ -11,13 +14,74 @@ type Symbols struct {
 	Starter    string
 	Terminator string
 	Horizontal string
+
+	// IndentWidth is the number of columns used by each level of the tree.
+	// When zero, it is computed from the widest symbol plus the Gap.
+	IndentWidth int
+	// Gap is the number of blank columns between the symbols and the node content.
+	// When zero, the default of one column is used, a negative value means no gap.
+	Gap int
+	// ExtendStarter fills the space left after the Starter and Terminator symbols
+	// with the Horizontal symbol, when IndentWidth is larger than them.
+	ExtendStarter bool
+	// Compact draws each level of the tree in a single column, using the first cell
+	// of every symbol and no gap.
+	Compact bool
+
+	// Expanded, Collapsed, Leaf and Loading are the markers drawn in the expander column
+	// of the Model. When empty, the DefaultExpanded, DefaultCollapsed, DefaultLeaf and
+	// DefaultLoading values are used.
+	Expanded  string
+	Collapsed string
+	Leaf      string
+	Loading   string
+}
+
+const (
+	DefaultExpanded  = "▾"
+	DefaultCollapsed = "▸"
+	DefaultLeaf      = " "
+	DefaultLoading   = "⋯"
+)
+
+// gap returns the number of blank columns between the symbols and the node content.
+func (s Symbols) gap() int {
+	switch {
+	case s.Compact || s.Gap < 0:
+		return 0
+	case s.Gap == 0:
+		return 1
+	default:
+		return s.Gap
+	}
 }
 
+// width returns the number of columns used by each level of the tree.
 func width(s Symbols) int {
-	co := utf8.RuneCount([]byte(s.Connector))
-	st := utf8.RuneCount([]byte(s.Starter))
-	te := utf8.RuneCount([]byte(s.Terminator))
-	return max(max(co, st), te) + 1
+	if s.Compact {
+		return 1
+	}
+	if s.IndentWidth > 0 {
+		return s.IndentWidth
+	}
+	co := ansi.StringWidth(s.Connector)
+	st := ansi.StringWidth(s.Starter)
+	te := ansi.StringWidth(s.Terminator)
+	return max(co, st, te) + s.gap()
+}
+
+// fit cuts the symbol sym to the columns available before the gap, and, for the symbols
+// which can be extended, fills the remaining space with the Horizontal symbol.
+func (s Symbols) fit(sym string, extend bool) string {
+	area := max(0, width(s)-s.gap())
+	sym = ansi.Truncate(sym, area, "")
+	if extend && s.ExtendStarter && ansi.StringWidth(s.Horizontal) > 0 {
+		for ansi.StringWidth(sym) < area {
+			sym += s.Horizontal
+		}
+		sym = ansi.Truncate(sym, area, "")
+	}
+	return sym
 }
 
 // Padding is expected to output a whitespace, or equivalent, used when two nodes
@@ -28,18 +92,18 @@ func Padding(style DepthStyler, s Symbols, depth int) string {
 
 // RenderTerminator is expected to output a terminator marker used for the last node in a list of nodes.
 func RenderTerminator(style DepthStyler, s Symbols, depth int) string {
-	return draw(style, s.Terminator, width(s), depth)
+	return draw(style, s.fit(s.Terminator, true), width(s), depth)
 }
 
 // RenderStarter is expected to output the marker used for every node in the tree.
 func RenderStarter(style DepthStyler, s Symbols, depth int) string {
-	return draw(style, s.Starter, width(s), depth)
+	return draw(style, s.fit(s.Starter, true), width(s), depth)
 }
 
 // RenderConnector is expected to output a continuator marker used to connect two nodes
 // which are children on the same parent.
 func RenderConnector(style DepthStyler, s Symbols, depth int) string {
-	return draw(style, s.Connector, width(s), depth)
+	return draw(style, s.fit(s.Connector, false), width(s), depth)
 }
 
 // DefaultSymbols returns a set of default Symbols for drawing the tree.
@@ -53,6 +117,7 @@ var (
 		Starter:    normalBorder.MiddleLeft + normalBorder.Bottom,
 		Connector:  normalBorder.Left + " ",
 		Terminator: normalBorder.BottomLeft + normalBorder.Bottom,
+		Horizontal: normalBorder.Bottom,
 	}
 
 	roundedBorder  = lipgloss.RoundedBorder()
@@ -60,6 +125,7 @@ var (
 		Starter:    roundedBorder.MiddleLeft + roundedBorder.Bottom,
 		Connector:  roundedBorder.Left + " ",
 		Terminator: roundedBorder.BottomLeft + roundedBorder.Bottom,
+		Horizontal: roundedBorder.Bottom,
 	}
 
 	thickBorder  = lipgloss.ThickBorder()
@@ -67,6 +133,7 @@ var (
 		Starter:    thickBorder.MiddleLeft + thickBorder.Bottom,
 		Connector:  thickBorder.Left + " ",
 		Terminator: thickBorder.BottomLeft + thickBorder.Bottom,
+		Horizontal: thickBorder.Bottom,
 	}
 
 	doubleBorder  = lipgloss.DoubleBorder()
@@ -74,6 +141,7 @@ var (
 		Starter:    doubleBorder.MiddleLeft + doubleBorder.Bottom,
 		Connector:  doubleBorder.Left + " ",
 		Terminator: doubleBorder.BottomLeft + doubleBorder.Bottom,
+		Horizontal: doubleBorder.Bottom,
 	}
 
 	normalEdgeSymbols = Symbols{
@@ -87,6 +155,16 @@ var (
 		Connector:  "┃",
 		Terminator: "╹",
 	}
+
+	asciiSymbols = Symbols{
+		Starter:    "|--",
+		Connector:  "|  ",
+		Terminator: "`--",
+		Horizontal: "-",
+		Expanded:   "-",
+		Collapsed:  "+",
+		Loading:    "~",
+	}
 )
 
 // NormalSymbols returns a standard-type symbols with a normal weight and 90
@@ -118,3 +196,36 @@ func NormalEdgeSymbols() Symbols {
 func ThickEdgeSymbols() Symbols {
 	return thickEdgeSymbols
 }
+
+// ASCIISymbols returns a symbols using only ASCII characters, for terminals which
+// can't display the box drawing characters.
+func ASCIISymbols() Symbols {
+	return asciiSymbols
+}
+
+// DetectSymbols returns the ASCIISymbols when the environment suggests the terminal
+// can't display the box drawing characters, and the DefaultSymbols otherwise.
+func DetectSymbols() Symbols {
+	if supportsUnicode(runtime.GOOS, os.Getenv) {
+		return DefaultSymbols()
+	}
+	return ASCIISymbols()
+}
+
+// supportsUnicode checks the locale and terminal type from the environment for UTF-8 support.
+// The locale variables are checked in the POSIX order of precedence.
+func supportsUnicode(goos string, getenv func(string) string) bool {
+	if getenv("TERM") == "linux" {
+		return false
+	}
+	if goos == "windows" && getenv("WT_SESSION") != "" {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			locale = strings.ToUpper(locale)
+			return strings.Contains(locale, "UTF-8") || strings.Contains(locale, "UTF8")
+		}
+	}
+	return false
+}
@@ -1,6 +1,14 @@
 package tree
 
-import "testing"
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
 
 func Test_width(t *testing.T) {
 	tests := []struct {
@@ -23,6 +31,36 @@ func Test_width(t *testing.T) {
 			arg:  normalEdgeSymbols,
 			want: 2,
 		},
+		{
+			name: "ascii symbols",
+			arg:  asciiSymbols,
+			want: 4,
+		},
+		{
+			name: "wide symbols",
+			arg:  Symbols{Starter: "界", Connector: "│", Terminator: "界"},
+			want: 3,
+		},
+		{
+			name: "indent width",
+			arg:  Symbols{Starter: "├─", IndentWidth: 6},
+			want: 6,
+		},
+		{
+			name: "gap",
+			arg:  Symbols{Starter: "├─", Gap: 3},
+			want: 5,
+		},
+		{
+			name: "no gap",
+			arg:  Symbols{Starter: "├─", Gap: -1},
+			want: 2,
+		},
+		{
+			name: "compact",
+			arg:  Symbols{Starter: "├─", IndentWidth: 6, Compact: true},
+			want: 1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -32,3 +70,210 @@ func Test_width(t *testing.T) {
 		})
 	}
 }
+
+func TestSymbols_golden(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols Symbols
+	}{
+		{name: "normal", symbols: NormalSymbols()},
+		{name: "rounded", symbols: RoundedSymbols()},
+		{name: "thick", symbols: ThickSymbols()},
+		{name: "double", symbols: DoubleSymbols()},
+		{name: "normal_edge", symbols: NormalEdgeSymbols()},
+		{name: "thick_edge", symbols: ThickEdgeSymbols()},
+		{name: "ascii", symbols: ASCIISymbols()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(Nodes{newTreeOne()}).RenderAll(WithSymbols(tt.symbols)) + "\n"
+
+			golden := filepath.Join("testdata", "symbols", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatalf("unable to update golden file: %s", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("unable to read golden file: %s", err)
+			}
+			if got != string(want) {
+				t.Errorf("RenderAll() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func Test_supportsUnicode(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want bool
+	}{
+		{
+			name: "empty environment",
+			goos: "linux",
+			env:  map[string]string{},
+			want: false,
+		},
+		{
+			name: "UTF-8 LANG",
+			goos: "linux",
+			env:  map[string]string{"LANG": "en_US.UTF-8"},
+			want: true,
+		},
+		{
+			name: "utf8 LANG",
+			goos: "linux",
+			env:  map[string]string{"LANG": "de_DE.utf8"},
+			want: true,
+		},
+		{
+			name: "LC_ALL overrides LANG",
+			goos: "linux",
+			env:  map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"},
+			want: false,
+		},
+		{
+			name: "LC_CTYPE overrides LANG",
+			goos: "linux",
+			env:  map[string]string{"LC_CTYPE": "en_US.UTF-8", "LANG": "C"},
+			want: true,
+		},
+		{
+			name: "linux console",
+			goos: "linux",
+			env:  map[string]string{"TERM": "linux", "LANG": "en_US.UTF-8"},
+			want: false,
+		},
+		{
+			name: "latin1 locale",
+			goos: "freebsd",
+			env:  map[string]string{"LANG": "fr_FR.ISO8859-1"},
+			want: false,
+		},
+		{
+			name: "windows terminal",
+			goos: "windows",
+			env:  map[string]string{"WT_SESSION": "some-id"},
+			want: true,
+		},
+		{
+			name: "windows console",
+			goos: "windows",
+			env:  map[string]string{},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(k string) string {
+				return tt.env[k]
+			}
+			if got := supportsUnicode(tt.goos, getenv); got != tt.want {
+				t.Errorf("supportsUnicode() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSymbols_geometry(t *testing.T) {
+	style := Style(defaultStyle)
+	tests := []struct {
+		name           string
+		symbols        Symbols
+		wantStarter    string
+		wantConnector  string
+		wantTerminator string
+	}{
+		{
+			name:           "normal",
+			symbols:        normalSymbols,
+			wantStarter:    "├─ ",
+			wantConnector:  "│  ",
+			wantTerminator: "└─ ",
+		},
+		{
+			name: "indent width",
+			symbols: Symbols{
+				Starter: "├─", Connector: "│", Terminator: "└─", Horizontal: "─",
+				IndentWidth: 5,
+			},
+			wantStarter:    "├─   ",
+			wantConnector:  "│    ",
+			wantTerminator: "└─   ",
+		},
+		{
+			name: "indent width - extended starter",
+			symbols: Symbols{
+				Starter: "├─", Connector: "│", Terminator: "└─", Horizontal: "─",
+				IndentWidth: 5, ExtendStarter: true,
+			},
+			wantStarter:    "├─── ",
+			wantConnector:  "│    ",
+			wantTerminator: "└─── ",
+		},
+		{
+			name: "indent width - narrower than the symbols",
+			symbols: Symbols{
+				Starter: "|--", Connector: "|  ", Terminator: "`--",
+				IndentWidth: 3,
+			},
+			wantStarter:    "|- ",
+			wantConnector:  "|  ",
+			wantTerminator: "`- ",
+		},
+		{
+			name: "gap",
+			symbols: Symbols{
+				Starter: "├─", Connector: "│", Terminator: "└─",
+				Gap: 2,
+			},
+			wantStarter:    "├─  ",
+			wantConnector:  "│   ",
+			wantTerminator: "└─  ",
+		},
+		{
+			name: "no gap",
+			symbols: Symbols{
+				Starter: "├─", Connector: "│", Terminator: "└─",
+				Gap: -1,
+			},
+			wantStarter:    "├─",
+			wantConnector:  "│ ",
+			wantTerminator: "└─",
+		},
+		{
+			name:           "compact",
+			symbols:        Symbols{Starter: "├─", Connector: "│ ", Terminator: "└─", Compact: true},
+			wantStarter:    "├",
+			wantConnector:  "│",
+			wantTerminator: "└",
+		},
+		{
+			name:           "wide symbols",
+			symbols:        Symbols{Starter: "界", Connector: "│", Terminator: "界"},
+			wantStarter:    "界 ",
+			wantConnector:  "│  ",
+			wantTerminator: "界 ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderStarter(style, tt.symbols, 0); got != tt.wantStarter {
+				t.Errorf("RenderStarter() = %q, want %q", got, tt.wantStarter)
+			}
+			if got := RenderConnector(style, tt.symbols, 0); got != tt.wantConnector {
+				t.Errorf("RenderConnector() = %q, want %q", got, tt.wantConnector)
+			}
+			if got := RenderTerminator(style, tt.symbols, 0); got != tt.wantTerminator {
+				t.Errorf("RenderTerminator() = %q, want %q", got, tt.wantTerminator)
+			}
+			if got, want := Padding(style, tt.symbols, 0), strings.Repeat(" ", width(tt.symbols)); got != want {
+				t.Errorf("Padding() = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package tree
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+
+	"charm.land/lipgloss/v2"
+)
+
+// Color is a color which can have different values on light and dark terminal backgrounds.
+// In theme files it can be a string, used for both, or an object with "light" and "dark" keys.
+type Color struct {
+	Light string `json:"light,omitempty" toml:"light,omitempty"`
+	Dark  string `json:"dark,omitempty" toml:"dark,omitempty"`
+}
+
+// UnmarshalJSON decodes a Color from either a string or a {"light": "", "dark": ""} object.
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		c.Light, c.Dark = s, s
+		return nil
+	}
+	type plain Color
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// UnmarshalTOML decodes a Color from either a string or a { light = "", dark = "" } table.
+// It is used by the github.com/BurntSushi/toml decoder.
+func (c *Color) UnmarshalTOML(data any) error {
+	switch v := data.(type) {
+	case string:
+		c.Light, c.Dark = v, v
+	case map[string]any:
+		c.Light, _ = v["light"].(string)
+		c.Dark, _ = v["dark"].(string)
+	default:
+		return fmt.Errorf("invalid color value %v", data)
+	}
+	return nil
+}
+
+func (c Color) color(isDark bool) color.Color {
+	v := c.Light
+	if isDark {
+		v = c.Dark
+	}
+	if v == "" {
+		return nil
+	}
+	return lipgloss.Color(v)
+}
+
+// ThemeStyle describes how one of the elements of the tree is styled.
+type ThemeStyle struct {
+	Foreground Color `json:"foreground" toml:"foreground"`
+	Background Color `json:"background" toml:"background"`
+	Bold       bool  `json:"bold,omitempty" toml:"bold,omitempty"`
+	Faint      bool  `json:"faint,omitempty" toml:"faint,omitempty"`
+	Italic     bool  `json:"italic,omitempty" toml:"italic,omitempty"`
+	Underline  bool  `json:"underline,omitempty" toml:"underline,omitempty"`
+	Reverse    bool  `json:"reverse,omitempty" toml:"reverse,omitempty"`
+}
+
+// Style returns the lipgloss.Style corresponding to s for a dark, or light, background.
+func (s ThemeStyle) Style(isDark bool) lipgloss.Style {
+	style := defaultStyle.Bold(s.Bold).Faint(s.Faint).Italic(s.Italic).Underline(s.Underline).Reverse(s.Reverse)
+	if fg := s.Foreground.color(isDark); fg != nil {
+		style = style.Foreground(fg)
+	}
+	if bg := s.Background.color(isDark); bg != nil {
+		style = style.Background(bg)
+	}
+	return style
+}
+
+// Theme describes the styles of all the elements of the tree.
+// Themes can be loaded from JSON files, or from other formats like TOML, with LoadTheme.
+//
+// There is no style for the search matches, as the tree has no search feature to style yet.
+type Theme struct {
+	Name string `json:"name" toml:"name"`
+	// Guides holds the styles for the tree symbols, one for each depth of the tree.
+	// When the tree is deeper than the number of styles, they are reused from the start.
+	Guides []ThemeStyle `json:"guides" toml:"guides"`
+	// ActiveGuide is the style of the tree symbols on the path to the selected node.
+	// When missing, they are rendered like the rest of the Guides.
+	ActiveGuide     *ThemeStyle `json:"active_guide,omitempty" toml:"active_guide,omitempty"`
+	Line            ThemeStyle  `json:"line" toml:"line"`
+	Selected        ThemeStyle  `json:"selected" toml:"selected"`
+	BlurredSelected ThemeStyle  `json:"blurred_selected" toml:"blurred_selected"`
+	Marked          ThemeStyle  `json:"marked" toml:"marked"`
+	Expander        ThemeStyle  `json:"expander" toml:"expander"`
+	Icon            ThemeStyle  `json:"icon" toml:"icon"`
+	Column          ThemeStyle  `json:"column" toml:"column"`
+	Scrollbar       ThemeStyle  `json:"scrollbar" toml:"scrollbar"`
+	ScrollbarThumb  ThemeStyle  `json:"scrollbar_thumb" toml:"scrollbar_thumb"`
+	Status          ThemeStyle  `json:"status" toml:"status"`
+}
+
+// Styles returns the tree Styles for the theme, on a dark, or light, background.
+func (t Theme) Styles(isDark bool) Styles {
+	guides := make(DepthStyles, len(t.Guides))
+	for i, g := range t.Guides {
+		guides[i] = g.Style(isDark)
+	}
+	s := Styles{
+		Line:            t.Line.Style(isDark),
+		Selected:        t.Selected.Style(isDark),
+		BlurredSelected: t.BlurredSelected.Style(isDark),
+		Marked:          t.Marked.Style(isDark),
+		Symbol:          guides,
+		Scrollbar:       t.Scrollbar.Style(isDark),
+		ScrollbarThumb:  t.ScrollbarThumb.Style(isDark),
+		Status:          t.Status.Style(isDark),
+		Expander:        t.Expander.Style(isDark),
+		Icon:            t.Icon.Style(isDark),
+		Column:          t.Column.Style(isDark),
+	}
+	if t.ActiveGuide != nil {
+		s.ActiveSymbol = Style(t.ActiveGuide.Style(isDark))
+	}
+	return s
+}
+
+// SetTheme sets the tree Styles from the theme. The Model keeps track of the terminal
+// background color, from tea.BackgroundColorMsg messages, and updates the styles when it changes.
+func (m *Model) SetTheme(t Theme) {
+	m.theme = &t
+	m.Styles = t.Styles(!m.lightBackground)
+}
+
+// setBackground records if the terminal background is dark and updates the themed styles.
+func (m *Model) setBackground(isDark bool) {
+	m.lightBackground = !isDark
+	if m.theme != nil {
+		m.Styles = m.theme.Styles(isDark)
+	}
+}
+
+// DepthStyles is a DepthStyler which uses a different style for each depth of the tree.
+// When the tree is deeper than the number of styles, they are reused from the start.
+type DepthStyles []lipgloss.Style
+
+// Render renders strs with the style corresponding to depth.
+func (d DepthStyles) Render(depth int, strs ...string) string {
+	if len(d) == 0 {
+		return defaultStyle.Render(strs...)
+	}
+	return d[depth%len(d)].Render(strs...)
+}
+
+// Width returns a copy of the styles with their width set to w.
+func (d DepthStyles) Width(w int) DepthStyler {
+	out := make(DepthStyles, len(d))
+	for i, s := range d {
+		out[i] = s.Width(w)
+	}
+	if len(d) == 0 {
+		out = DepthStyles{defaultStyle.Width(w)}
+	}
+	return out
+}
+
+// ThemeDecoder decodes the contents of r into v, which is a pointer to a Theme.
+// For TOML files, github.com/BurntSushi/toml can be used:
+//
+//	func(r io.Reader, v any) error {
+//		_, err := toml.NewDecoder(r).Decode(v)
+//		return err
+//	}
+type ThemeDecoder func(r io.Reader, v any) error
+
+// DecodeJSON is the ThemeDecoder for JSON themes.
+func DecodeJSON(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// LoadTheme reads a Theme from a file, using decode. When decode is nil, the file is read as JSON.
+func LoadTheme(path string, decode ThemeDecoder) (Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	defer f.Close()
+
+	return DecodeTheme(f, decode)
+}
+
+// DecodeTheme reads a Theme from r, using decode. When decode is nil, r is read as JSON.
+func DecodeTheme(r io.Reader, decode ThemeDecoder) (Theme, error) {
+	if decode == nil {
+		decode = DecodeJSON
+	}
+	t := Theme{}
+	if err := decode(r, &t); err != nil {
+		return t, fmt.Errorf("unable to decode theme: %w", err)
+	}
+	return t, nil
+}
+
+// DefaultTheme returns the Theme corresponding to the DefaultStyles.
+func DefaultTheme() Theme {
+	return Themes["default"]
+}
+
+// Themes holds the bundled themes, by name.
+var Themes = map[string]Theme{
+	"default": {
+		Name:            "default",
+		Guides:          []ThemeStyle{{}},
+		Selected:        ThemeStyle{Reverse: true},
+		BlurredSelected: ThemeStyle{Reverse: true, Faint: true},
+		Marked:          ThemeStyle{Bold: true},
+		Scrollbar:       ThemeStyle{Faint: true},
+		Status:          ThemeStyle{Faint: true},
+	},
+	"solarized": {
+		Name: "solarized",
+		Guides: []ThemeStyle{
+			{Foreground: Color{Light: "#93a1a1", Dark: "#586e75"}},
+		},
+		ActiveGuide:     &ThemeStyle{Foreground: Color{Light: "#268bd2", Dark: "#268bd2"}},
+		Line:            ThemeStyle{Foreground: Color{Light: "#657b83", Dark: "#839496"}},
+		Selected:        ThemeStyle{Foreground: Color{Light: "#fdf6e3", Dark: "#002b36"}, Background: Color{Light: "#268bd2", Dark: "#268bd2"}},
+		BlurredSelected: ThemeStyle{Background: Color{Light: "#eee8d5", Dark: "#073642"}},
+		Marked:          ThemeStyle{Foreground: Color{Light: "#cb4b16", Dark: "#cb4b16"}, Bold: true},
+		Expander:        ThemeStyle{Foreground: Color{Light: "#2aa198", Dark: "#2aa198"}},
+		Icon:            ThemeStyle{Foreground: Color{Light: "#6c71c4", Dark: "#6c71c4"}},
+		Scrollbar:       ThemeStyle{Foreground: Color{Light: "#eee8d5", Dark: "#073642"}},
+		ScrollbarThumb:  ThemeStyle{Foreground: Color{Light: "#93a1a1", Dark: "#586e75"}},
+		Status:          ThemeStyle{Foreground: Color{Light: "#93a1a1", Dark: "#586e75"}},
+	},
+	"nord": {
+		Name: "nord",
+		Guides: []ThemeStyle{
+			{Foreground: Color{Light: "#5E81AC", Dark: "#81A1C1"}},
+			{Foreground: Color{Light: "#A3BE8C", Dark: "#A3BE8C"}},
+			{Foreground: Color{Light: "#B48EAD", Dark: "#B48EAD"}},
+			{Foreground: Color{Light: "#D08770", Dark: "#EBCB8B"}},
+		},
+		ActiveGuide:     &ThemeStyle{Foreground: Color{Light: "#2E3440", Dark: "#ECEFF4"}, Bold: true},
+		Line:            ThemeStyle{Foreground: Color{Light: "#2E3440", Dark: "#D8DEE9"}},
+		Selected:        ThemeStyle{Foreground: Color{Light: "#ECEFF4", Dark: "#2E3440"}, Background: Color{Light: "#5E81AC", Dark: "#88C0D0"}},
+		BlurredSelected: ThemeStyle{Background: Color{Light: "#D8DEE9", Dark: "#434C5E"}},
+		Marked:          ThemeStyle{Foreground: Color{Light: "#BF616A", Dark: "#BF616A"}, Bold: true},
+		Expander:        ThemeStyle{Foreground: Color{Light: "#5E81AC", Dark: "#88C0D0"}},
+		Icon:            ThemeStyle{Foreground: Color{Light: "#5E81AC", Dark: "#88C0D0"}},
+		Scrollbar:       ThemeStyle{Foreground: Color{Light: "#D8DEE9", Dark: "#3B4252"}},
+		ScrollbarThumb:  ThemeStyle{Foreground: Color{Light: "#4C566A", Dark: "#81A1C1"}},
+		Status:          ThemeStyle{Foreground: Color{Light: "#4C566A", Dark: "#4C566A"}},
+	},
+	"high-contrast": {
+		Name:            "high-contrast",
+		Guides:          []ThemeStyle{{Foreground: Color{Light: "#000000", Dark: "#ffffff"}}},
+		ActiveGuide:     &ThemeStyle{Foreground: Color{Light: "#0000ff", Dark: "#ffff00"}, Bold: true},
+		Line:            ThemeStyle{Foreground: Color{Light: "#000000", Dark: "#ffffff"}},
+		Selected:        ThemeStyle{Foreground: Color{Light: "#ffffff", Dark: "#000000"}, Background: Color{Light: "#000000", Dark: "#ffffff"}, Bold: true},
+		BlurredSelected: ThemeStyle{Underline: true},
+		Marked:          ThemeStyle{Foreground: Color{Light: "#ff0000", Dark: "#ff5555"}, Bold: true},
+		Expander:        ThemeStyle{Bold: true},
+		Scrollbar:       ThemeStyle{Foreground: Color{Light: "#808080", Dark: "#808080"}},
+		ScrollbarThumb:  ThemeStyle{Foreground: Color{Light: "#000000", Dark: "#ffffff"}},
+		Status:          ThemeStyle{Bold: true},
+	},
+}
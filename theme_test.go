@@ -0,0 +1,213 @@
+package tree
+
+import (
+	"errors"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+var customTheme = Theme{
+	Name: "custom",
+	Guides: []ThemeStyle{
+		{Foreground: Color{Light: "#ff0000", Dark: "#ff0000"}},
+		{Foreground: Color{Light: "#00ff00", Dark: "#0000ff"}},
+	},
+	ActiveGuide:     &ThemeStyle{Foreground: Color{Light: "#ffffff", Dark: "#ffffff"}, Bold: true},
+	Selected:        ThemeStyle{Reverse: true},
+	BlurredSelected: ThemeStyle{Background: Color{Light: "#eeeeee", Dark: "#333333"}},
+}
+
+func TestLoadTheme(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Theme
+		wantErr bool
+	}{
+		{path: "testdata/themes/custom.json", want: customTheme},
+		{path: "testdata/themes/missing.json", wantErr: true},
+		{path: "testdata/symbols/normal.golden", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := LoadTheme(tt.path, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadTheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("LoadTheme() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeTheme(t *testing.T) {
+	named := func(r io.Reader, v any) error {
+		name, err := io.ReadAll(r)
+		v.(*Theme).Name = string(name)
+		return err
+	}
+	got, err := DecodeTheme(strings.NewReader("custom"), named)
+	if err != nil {
+		t.Fatalf("DecodeTheme() error = %v", err)
+	}
+	if got.Name != "custom" {
+		t.Errorf("DecodeTheme() Name = %q, want %q", got.Name, "custom")
+	}
+}
+
+func TestDecodeTheme_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode ThemeDecoder
+		data   string
+	}{
+		{name: "json syntax", data: `{"name": `},
+		{name: "json color", data: `{"line": {"foreground": 12}}`},
+		{name: "decoder", decode: func(io.Reader, any) error { return errors.New("invalid") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeTheme(strings.NewReader(tt.data), tt.decode); err == nil {
+				t.Errorf("DecodeTheme() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestColor_UnmarshalTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		want    Color
+		wantErr bool
+	}{
+		{name: "string", data: "#ff0000", want: Color{Light: "#ff0000", Dark: "#ff0000"}},
+		{name: "table", data: map[string]any{"light": "#00ff00", "dark": "#0000ff"}, want: Color{Light: "#00ff00", Dark: "#0000ff"}},
+		{name: "number", data: int64(12), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Color{}
+			if err := got.UnmarshalTOML(tt.data); (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalTOML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalTOML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColor_color(t *testing.T) {
+	tests := []struct {
+		name   string
+		c      Color
+		isDark bool
+		want   color.Color
+	}{
+		{name: "empty", c: Color{}, isDark: true, want: nil},
+		{name: "light", c: Color{Light: "#ffffff", Dark: "#000000"}, isDark: false, want: lipgloss.Color("#ffffff")},
+		{name: "dark", c: Color{Light: "#ffffff", Dark: "#000000"}, isDark: true, want: lipgloss.Color("#000000")},
+		{name: "only light", c: Color{Light: "#ffffff"}, isDark: true, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.color(tt.isDark); got != tt.want {
+				t.Errorf("color() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTheme_Styles(t *testing.T) {
+	tests := []struct {
+		name          string
+		isDark        bool
+		wantGuide1    color.Color
+		wantBlurredBg color.Color
+	}{
+		{name: "dark", isDark: true, wantGuide1: lipgloss.Color("#0000ff"), wantBlurredBg: lipgloss.Color("#333333")},
+		{name: "light", isDark: false, wantGuide1: lipgloss.Color("#00ff00"), wantBlurredBg: lipgloss.Color("#eeeeee")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := customTheme.Styles(tt.isDark)
+			guides, ok := s.Symbol.(DepthStyles)
+			if !ok || len(guides) != 2 {
+				t.Fatalf("Symbol = %T %v, want two DepthStyles", s.Symbol, s.Symbol)
+			}
+			if got := guides[1].GetForeground(); got != tt.wantGuide1 {
+				t.Errorf("Guides[1] foreground = %v, want %v", got, tt.wantGuide1)
+			}
+			if got := s.BlurredSelected.GetBackground(); got != tt.wantBlurredBg {
+				t.Errorf("BlurredSelected background = %v, want %v", got, tt.wantBlurredBg)
+			}
+			if !s.Selected.GetReverse() {
+				t.Errorf("Selected is not reversed")
+			}
+			active, ok := s.ActiveSymbol.(Style)
+			if !ok || !lipgloss.Style(active).GetBold() {
+				t.Errorf("ActiveSymbol = %v, want a bold Style", s.ActiveSymbol)
+			}
+		})
+	}
+}
+
+func TestThemes(t *testing.T) {
+	for name, theme := range Themes {
+		t.Run(name, func(t *testing.T) {
+			if theme.Name != name {
+				t.Errorf("Name = %q, want %q", theme.Name, name)
+			}
+			if len(theme.Guides) == 0 {
+				t.Errorf("theme has no guides")
+			}
+			for _, isDark := range []bool{true, false} {
+				m := mockModel(newTreeOne())
+				if got := m.RenderAll(WithStyles(theme.Styles(isDark))); !strings.Contains(got, "example1") {
+					t.Errorf("RenderAll() with theme = %q, missing nodes", got)
+				}
+			}
+		})
+	}
+}
+
+func TestDepthStyles(t *testing.T) {
+	d := DepthStyles{defaultStyle.Foreground(lipgloss.Color("1")), defaultStyle.Foreground(lipgloss.Color("2"))}
+	w := d.Width(3).(DepthStyles)
+	for i := range w {
+		if got := w[i].GetWidth(); got != 3 {
+			t.Errorf("Width(3)[%d] width = %d, want 3", i, got)
+		}
+	}
+	if d[0].GetWidth() != 0 {
+		t.Errorf("Width() modified the receiver")
+	}
+	if got, want := d.Render(2, "x"), d[0].Render("x"); got != want {
+		t.Errorf("Render(2) = %q, want the style of depth 0 %q", got, want)
+	}
+	if got := (DepthStyles{}).Width(2).Render(5, "x"); got != "x " {
+		t.Errorf("empty DepthStyles Render() = %q, want %q", got, "x ")
+	}
+}
+
+func TestModel_SetTheme_background(t *testing.T) {
+	m := New(Nodes{newTreeOne()})
+	m.SetTheme(customTheme)
+	if got := m.Styles.BlurredSelected.GetBackground(); got != lipgloss.Color("#333333") {
+		t.Fatalf("BlurredSelected background = %v, want the dark color", got)
+	}
+	m.Update(tea.BackgroundColorMsg{Color: lipgloss.Color("#ffffff")})
+	if got := m.Styles.BlurredSelected.GetBackground(); got != lipgloss.Color("#eeeeee") {
+		t.Errorf("BlurredSelected background = %v, want the light color", got)
+	}
+}
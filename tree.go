@@ -2,6 +2,8 @@ package tree
 
 import (
 	"math"
+	"slices"
+	"strconv"
 	"strings"
 
 	"charm.land/bubbles/v2/key"
@@ -44,8 +46,13 @@ type KeyMap struct {
 	HalfPageDown key.Binding
 	GotoTop      key.Binding
 	GotoBottom   key.Binding
+	ScrollLeft   key.Binding
+	ScrollRight  key.Binding
+	GotoSticky   key.Binding
 
-	Expand key.Binding
+	Expand  key.Binding
+	Hoist   key.Binding
+	Unhoist key.Binding
 }
 
 // DefaultKeyMap returns a default set of keybindings.
@@ -83,10 +90,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("end", "G"),
 			key.WithHelp("G/end", "go to end"),
 		),
+		ScrollLeft: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "scroll left"),
+		),
+		ScrollRight: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "scroll right"),
+		),
+		GotoSticky: key.NewBinding(
+			key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+			key.WithHelp("1-9", "go to sticky line"),
+		),
 		Expand: key.NewBinding(
 			key.WithKeys("o"),
 			key.WithHelp("o", "toggle expand for current node"),
 		),
+		Hoist: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "hoist current node"),
+		),
+		Unhoist: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", "undo the last hoist"),
+		),
 	}
 }
 
@@ -111,15 +138,34 @@ func (s Style) Width(w int) DepthStyler {
 type Styles struct {
 	Line     lipgloss.Style
 	Selected lipgloss.Style
-	Symbol   DepthStyler
+	// BlurredSelected is used for the selected node when the tree is not focused.
+	BlurredSelected lipgloss.Style
+	Marked          lipgloss.Style
+	Symbol          DepthStyler
+	// ActiveSymbol, when set, is used for the tree symbols on the path from the root to the selected node.
+	ActiveSymbol   DepthStyler
+	Scrollbar      lipgloss.Style
+	ScrollbarThumb lipgloss.Style
+	Status         lipgloss.Style
+	Expander       lipgloss.Style
+	Icon           lipgloss.Style
+	Column         lipgloss.Style
 }
 
 // DefaultStyles returns a set of default style definitions for this tree.
 func DefaultStyles() Styles {
 	return Styles{
-		Line:     defaultStyle,
-		Selected: defaultSelectedStyle,
-		Symbol:   Style(defaultSymbolStyle),
+		Line:            defaultStyle,
+		Selected:        defaultSelectedStyle,
+		BlurredSelected: defaultSelectedStyle.Faint(true),
+		Marked:          defaultStyle.Bold(true),
+		Symbol:          Style(defaultSymbolStyle),
+		Scrollbar:       defaultStyle.Faint(true),
+		ScrollbarThumb:  defaultStyle,
+		Status:          defaultStyle.Faint(true),
+		Expander:        defaultStyle,
+		Icon:            defaultStyle,
+		Column:          defaultStyle,
 	}
 }
 
@@ -138,10 +184,14 @@ func (m *Model) setCurrentNode(cursor int) tea.Cmd {
 			previous.Update(previous.State() ^ NodeSelected)
 		}
 		m.cursor = cursor
+		if m.ScrollFollowsCursor {
+			m.xOffset = 0
+		}
 	}
 	if current := m.currentNode(); current != nil {
-		current.Update(current.State() | NodeSelected)
-		return m.positionChanged
+		// The node can react to being selected with a command of its own.
+		_, cmd := current.Update(current.State() | NodeSelected)
+		return tea.Batch(m.positionChanged, cmd)
 	}
 	return noop
 }
@@ -165,8 +215,42 @@ type Model struct {
 	Styles  Styles
 	Symbols Symbols
 
-	focus  bool
-	cursor int
+	// Overflow sets how the nodes wider than the viewport are displayed.
+	Overflow Overflow
+	// ScrollFollowsCursor limits horizontal scrolling to the row under the cursor,
+	// the offset being reset every time the cursor moves.
+	ScrollFollowsCursor bool
+	// StickyLines sets the maximum number of ancestors of the top row which are pinned
+	// to the top of the viewport while scrolling. Zero disables sticky scrolling.
+	StickyLines int
+	// ShowScrollbar renders a vertical scrollbar in the rightmost column of the viewport.
+	ShowScrollbar bool
+	// ShowStatus renders a status line with the cursor position on the last line of the viewport.
+	ShowStatus bool
+	// ShowExpander renders a column between the tree symbols and the node content, which
+	// shows if the node is expanded, collapsed or loading its children.
+	ShowExpander bool
+	// Icons provides the icons of the nodes which don't implement Iconer. A column with
+	// the icons is added before the node content when any of the visible nodes has one.
+	Icons IconProvider
+	// Columns are rendered at the left of the tree symbols, in order.
+	Columns []Column
+
+	focus     bool
+	cursor    int
+	xOffset   int
+	expandAll bool
+	// activePath caches the nodes from the root to the selected node during rendering.
+	activePath Nodes
+	// hoisted holds the stack of nodes the tree has been hoisted into.
+	hoisted []hoist
+	// iconWidth caches the width of the icon column during rendering.
+	iconWidth int
+	// columnWidths caches the widths of the Columns during rendering.
+	columnWidths []int
+
+	theme           *Theme
+	lightBackground bool
 
 	tree Nodes
 }
@@ -192,6 +276,15 @@ func (m *Model) GotoTop() tea.Cmd {
 	return m.SetCursor(0)
 }
 
+// GotoNode moves the selection to the node n, if it is currently visible in the tree.
+func (m *Model) GotoNode(n Node) tea.Cmd {
+	i := m.tree.sequentialNodes().indexOf(n)
+	if i < 0 {
+		return noop
+	}
+	return m.SetCursor(i)
+}
+
 // PastBottom returns whether the viewport is scrolled beyond the last
 // line. This can happen when adjusting the viewport height.
 func (m *Model) PastBottom() bool {
@@ -213,6 +306,59 @@ func expanded(n Node) tea.Cmd {
 	}
 }
 
+// ChangedMsg notifies the Model that nodes were added to, removed from, or modified in
+// the tree by something else than the Model itself, like a file system watcher.
+// The Model keeps the selection on the same node, if it is still visible. When the selected
+// node, or one of its ancestors, was removed, the node which took its place gets selected.
+type ChangedMsg struct {
+	Added    Nodes
+	Removed  Nodes
+	Modified Nodes
+}
+
+// Refresh moves the cursor back to the selected node after the tree was changed by something
+// else than the Model. When the selected node is not visible anymore, the node which took its
+// place gets selected.
+func (m *Model) Refresh() tea.Cmd {
+	cursor := m.cursor
+	if i := m.tree.sequentialNodes().indexOf(m.selectedNode()); i >= 0 {
+		cursor = i
+	}
+	m.cursor = -1
+	return m.SetCursor(cursor)
+}
+
+// changed deselects the removed nodes, and their descendants, before refreshing the cursor.
+func (m *Model) changed(msg ChangedMsg) tea.Cmd {
+	for _, n := range msg.Removed {
+		deselect(n)
+	}
+	return m.Refresh()
+}
+
+// deselect clears the NodeSelected state of node n and of its descendants.
+func deselect(n Node) {
+	if n == nil {
+		return
+	}
+	if isSelected(n) {
+		n.Update(n.State() &^ NodeSelected)
+	}
+	for _, c := range n.Children() {
+		deselect(c)
+	}
+}
+
+// selectedNode returns the first visible node with the NodeSelected state.
+func (m *Model) selectedNode() Node {
+	for _, n := range m.tree.sequentialNodes() {
+		if isSelected(n) {
+			return n
+		}
+	}
+	return nil
+}
+
 // ToggleExpand toggles the expanded state of the node pointed at by m.cursor
 func (m *Model) ToggleExpand() tea.Cmd {
 	n := m.currentNode()
@@ -222,13 +368,15 @@ func (m *Model) ToggleExpand() tea.Cmd {
 	if !isCollapsible(n) {
 		return noop
 	}
-	n.Update(n.State() ^ NodeCollapsed)
-	return expanded(n)
+	// The node can react to being expanded with a command of its own, like loading its children.
+	_, cmd := n.Update(n.State() ^ NodeCollapsed)
+	return tea.Batch(expanded(n), cmd)
 }
 
 // SetWidth sets the width of the viewport of the tree.
 func (m *Model) SetWidth(w int) {
 	m.Model.SetWidth(w)
+	m.xOffset = clamp(m.xOffset, 0, m.maxXOffset())
 }
 
 // SetHeight sets the height of the viewport of the tree.
@@ -260,12 +408,14 @@ func (m *Model) SetYOffset(n int) {
 
 // ScrollPercent returns the amount scrolled as a float between 0 and 1.
 func (m *Model) ScrollPercent() float64 {
-	if m.Model.Height() >= len(m.tree.sequentialNodes()) {
+	offsets := m.lineOffsets(m.tree.sequentialNodes())
+	total := offsets[len(offsets)-1]
+	if m.rowsHeight() >= total {
 		return 1.0
 	}
 	y := float64(m.Model.YOffset())
-	h := float64(m.Model.Height())
-	t := float64(len(m.tree.sequentialNodes()))
+	h := float64(m.rowsHeight())
+	t := float64(total)
 	v := y / (t - h)
 	return math.Max(0.0, math.Min(1.0, v))
 }
@@ -277,22 +427,69 @@ func (m *Model) Cursor() int {
 
 // SetCursor returns the index of the selected row.
 func (m *Model) SetCursor(pos int) tea.Cmd {
-	cursor := clamp(pos, 0, len(m.tree.sequentialNodes())-1)
+	visible := m.tree.sequentialNodes()
+	cursor := clamp(pos, 0, len(visible)-1)
 	if cursor == m.cursor {
 		return noop
 	}
 
-	yOffset := -1
-	if cursor < m.Model.YOffset() {
-		yOffset = cursor
+	if cursor >= 0 {
+		m.scrollToNode(visible, m.lineOffsets(visible), cursor)
 	}
-	if cursor > (m.Model.YOffset() + (m.Model.Height() - 1)) {
-		yOffset = cursor - m.Model.Height() + 1
+	return m.setCurrentNode(cursor)
+}
+
+// scrollToNode scrolls the viewport the least needed for the row at index i to be visible,
+// below the sticky lines. The rows of multi-line and wrapped nodes span several lines, as given by offsets.
+func (m *Model) scrollToNode(visible Nodes, offsets []int, i int) {
+	first, last := offsets[i], offsets[i+1]-1
+	yOffset := m.Model.YOffset()
+	if last > (yOffset + (m.rowsHeight() - 1)) {
+		// NOTE(marius): a row taller than the viewport is shown from its first line
+		yOffset = min(first, last-m.rowsHeight()+1)
+	}
+	if first < yOffset {
+		yOffset = first
 	}
-	if yOffset > -1 {
+	// The sticky lines cover the rows at the top of the viewport, the ones of the root nodes
+	// being always visible.
+	for yOffset > 0 && first-yOffset < len(m.stickyNodesAt(visible, offsets, yOffset)) {
+		yOffset--
+	}
+	if yOffset != m.Model.YOffset() {
 		m.Model.SetYOffset(yOffset)
 	}
-	return m.setCurrentNode(cursor)
+}
+
+// nodeHeight returns the number of lines of the row of node t, without its children.
+func (m *Model) nodeHeight(t Node) int {
+	if m.Overflow == OverflowWrap {
+		return lipgloss.Height(m.renderRow(t))
+	}
+	return lipgloss.Height(t.View().Content)
+}
+
+// lineOffsets returns the first line of the row of every node in the visible slice,
+// followed by the total number of lines.
+func (m *Model) lineOffsets(visible Nodes) []int {
+	offsets := make([]int, len(visible)+1)
+	for i, n := range visible {
+		offsets[i+1] = offsets[i] + m.nodeHeight(n)
+	}
+	return offsets
+}
+
+// nodeAtLine returns the index of the row which covers the line, using the offsets
+// computed by lineOffsets. It returns -1 for the lines outside the rows.
+func nodeAtLine(offsets []int, line int) int {
+	if line < 0 || line >= offsets[len(offsets)-1] {
+		return -1
+	}
+	i, found := slices.BinarySearch(offsets, line)
+	if !found {
+		i--
+	}
+	return i
 }
 
 type Msg string
@@ -312,7 +509,11 @@ func (m *Model) init() tea.Msg {
 }
 
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(m.init, m.setCurrentNode(0))
+	cmds := []tea.Cmd{m.init, m.setCurrentNode(0)}
+	if m.theme != nil {
+		cmds = append(cmds, tea.RequestBackgroundColor)
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m *Model) updateNodeVisibility(start, height int) tea.Cmd {
@@ -320,7 +521,14 @@ func (m *Model) updateNodeVisibility(start, height int) tea.Cmd {
 		return noop
 	}
 	visibleNodes := m.tree.sequentialNodes()
-	end := start + min(len(visibleNodes), height)
+	offsets := m.lineOffsets(visibleNodes)
+	if i := nodeAtLine(offsets, start); i >= 0 {
+		start = i
+	}
+	end := len(visibleNodes)
+	if i := nodeAtLine(offsets, start+height); i >= 0 {
+		end = i
+	}
 
 	cmds := make([]tea.Cmd, 0)
 	for i, nn := range visibleNodes {
@@ -346,9 +554,14 @@ func (m *Model) updateNodeVisibility(start, height int) tea.Cmd {
 
 // Update is the Tea update function which binds keystrokes to pagination.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if !m.focus {
-		return m, noop
+	switch msg.(type) {
+	case tea.KeyPressMsg, tea.MouseMsg:
+		// A blurred tree ignores the user input, but it keeps being resized and scrolled.
+		if !m.focus {
+			return m, noop
+		}
 	}
+	m.refreshHoisted()
 
 	var err error
 	var cmd tea.Cmd
@@ -365,22 +578,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(mm, m.KeyMap.LineDown):
 			cmd = m.MoveDown(1)
 		case key.Matches(mm, m.KeyMap.PageUp):
-			cmd = m.MoveUp(m.Model.Height() - 1)
+			cmd = m.MoveUp(m.rowsHeight() - 1)
 		case key.Matches(mm, m.KeyMap.PageDown):
-			cmd = m.MoveDown(m.Model.Height() - 1)
+			cmd = m.MoveDown(m.rowsHeight() - 1)
 		case key.Matches(mm, m.KeyMap.HalfPageUp):
-			cmd = m.MoveUp(m.Model.Height() / 2)
+			cmd = m.MoveUp(m.rowsHeight() / 2)
 		case key.Matches(mm, m.KeyMap.HalfPageDown):
-			cmd = m.MoveDown(m.Model.Height() / 2)
+			cmd = m.MoveDown(m.rowsHeight() / 2)
 		case key.Matches(mm, m.KeyMap.LineDown):
 			cmd = m.MoveDown(1)
 		case key.Matches(mm, m.KeyMap.GotoTop):
 			cmd = m.GotoTop()
 		case key.Matches(mm, m.KeyMap.GotoBottom):
 			cmd = m.GotoBottom()
+		case key.Matches(mm, m.KeyMap.ScrollLeft):
+			m.ScrollLeft(horizontalStep)
+		case key.Matches(mm, m.KeyMap.ScrollRight):
+			m.ScrollRight(horizontalStep)
+		case key.Matches(mm, m.KeyMap.GotoSticky):
+			if line, err := strconv.Atoi(mm.String()); err == nil {
+				cmd = m.GotoSticky(line - 1)
+			}
 		case key.Matches(mm, m.KeyMap.Expand):
 			cmd = m.ToggleExpand()
+		case key.Matches(mm, m.KeyMap.Hoist):
+			cmd = m.HoistCurrent()
+		case key.Matches(mm, m.KeyMap.Unhoist):
+			cmd = m.Unhoist()
 		}
+	case tea.MouseClickMsg:
+		cmd = m.click(mm.Mouse())
+	case GotoNodeMsg:
+		cmd = m.gotoNode(mm.Node)
+	case ChangedMsg:
+		cmd = m.changed(mm)
+	case tea.BackgroundColorMsg:
+		m.setBackground(mm.IsDark())
 	}
 
 	if err != nil {
@@ -393,11 +626,39 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // View renders the pagination to a string.
 func (m *Model) View() tea.View {
 	if renderedRows := m.render(); len(renderedRows) > 0 {
+		if m.ShowStatus {
+			// NOTE(marius): the empty row allows the last node to be scrolled above the status line
+			renderedRows = append(renderedRows, "")
+		}
 		m.Model.SetContent(
 			lipgloss.JoinVertical(lipgloss.Left, renderedRows...),
 		)
 	}
-	return tea.NewView(m.Model.View())
+	view := m.overlaySticky(m.Model.View())
+	view = m.overlayScrollbar(view)
+	view = m.overlayStatus(view)
+	return tea.NewView(view)
+}
+
+// click moves the selection to the row under the mouse pointer, or to the ancestor
+// if the row is a sticky line. The coordinates are expected to be relative to the
+// top left corner of the tree.
+func (m *Model) click(mouse tea.Mouse) tea.Cmd {
+	if mouse.Button != tea.MouseLeft || mouse.Y < 0 || mouse.Y >= m.rowsHeight() {
+		return noop
+	}
+	if sticky := m.stickyNodes(); mouse.Y < len(sticky) {
+		return m.GotoNode(sticky[mouse.Y])
+	}
+	i := nodeAtLine(m.lineOffsets(m.tree.sequentialNodes()), m.YOffset()+mouse.Y)
+	if i < 0 {
+		return noop
+	}
+	cmd := m.SetCursor(i)
+	if n := m.tree.at(i); n != nil && m.onExpander(n, mouse.X) {
+		return tea.Batch(cmd, m.ToggleExpand())
+	}
+	return cmd
 }
 
 // Focused returns the focus state of the tree.
@@ -406,14 +667,14 @@ func (m *Model) Focused() bool {
 }
 
 // Focus focuses the tree, allowing the user to move around the tree nodes.
-// interact.
+// The cursor is kept where it was when the tree was blurred.
 func (m *Model) Focus() {
 	m.focus = true
 }
 
-// Blur blurs the tree, preventing selection or movement.
+// Blur blurs the tree, preventing the user from moving the selection with the keyboard or the mouse.
+// The cursor position is kept, and the selected node is rendered with the BlurredSelected style.
 func (m *Model) Blur() {
-	m.cursor = -1
 	m.focus = false
 }
 
@@ -439,7 +700,7 @@ func (m *Model) getTreeSymbolForPos(n Node, pos, maxDepth int) string {
 	if n == nil {
 		return ""
 	}
-	s := m.Styles.Symbol
+	s := m.symbolStyle(n, pos, maxDepth)
 	if renderPaddingAtPos(n, pos, maxDepth) {
 		return Padding(s, m.Symbols, pos)
 	}
@@ -452,6 +713,55 @@ func (m *Model) getTreeSymbolForPos(n Node, pos, maxDepth int) string {
 	return RenderStarter(s, m.Symbols, pos)
 }
 
+// symbolStyle returns the style for the tree symbol of node n at position pos.
+func (m *Model) symbolStyle(n Node, pos, maxDepth int) DepthStyler {
+	if m.Styles.ActiveSymbol != nil && m.isActiveGuide(n, pos, maxDepth) {
+		return m.Styles.ActiveSymbol
+	}
+	return m.Styles.Symbol
+}
+
+// updateActivePath refreshes the path from the root to the selected node.
+func (m *Model) updateActivePath() {
+	m.activePath = nil
+	if m.Styles.ActiveSymbol == nil {
+		return
+	}
+	if cur := m.currentNode(); cur != nil {
+		m.activePath = append(m.ancestors(cur), cur)
+	}
+}
+
+// isActiveGuide checks if the tree symbol of node n at position pos is part of the line
+// connecting the root to the selected node. That is the case when the ancestor of n
+// at that position is the selected node's ancestor at the same level, or one of its
+// previous siblings.
+func (m *Model) isActiveGuide(n Node, pos, maxDepth int) bool {
+	if n == nil || pos >= len(m.activePath) || pos > maxDepth {
+		return false
+	}
+	a := n
+	for i := maxDepth; i > pos && a != nil; i-- {
+		a = a.Parent()
+	}
+	if a == nil {
+		return false
+	}
+	active := m.activePath[pos]
+	if a == active {
+		return pos == maxDepth
+	}
+	if a.Parent() != active.Parent() {
+		return false
+	}
+	siblings := m.tree
+	if p := active.Parent(); p != nil {
+		siblings = p.Children()
+	}
+	i := siblings.indexOf(a)
+	return i >= 0 && i < siblings.indexOf(active)
+}
+
 // renderPaddingAtPos computes the tree symbol for a Node for a specific depth
 func renderPaddingAtPos(n Node, depth, maxDepth int) bool {
 	if n == nil {
@@ -473,7 +783,7 @@ func renderPaddingAtPos(n Node, depth, maxDepth int) bool {
 }
 
 func (m *Model) renderPrefixForSingleLineNode(t Node) string {
-	maxDepth := getDepth(t)
+	maxDepth := m.depth(t)
 
 	prefix := strings.Builder{}
 	for pos := 0; pos <= maxDepth; pos++ {
@@ -483,17 +793,15 @@ func (m *Model) renderPrefixForSingleLineNode(t Node) string {
 }
 
 func (m *Model) renderPrefixForMultiLineNode(t Node, lineCount int) string {
-	maxDepth := getDepth(t)
+	maxDepth := m.depth(t)
 
-	s := m.Styles.Symbol
+	s := m.symbolStyle(t, maxDepth, maxDepth)
 
 	prefix := strings.Builder{}
 
 	connectsBottom := isLastNode(t)
 	for line := 0; line < lineCount; line++ {
-		for lvl := 0; lvl <= maxDepth-1; lvl++ {
-			prefix.WriteString(m.getTreeSymbolForPos(t, lvl, maxDepth))
-		}
+		prefix.WriteString(m.renderAncestorSymbols(t, maxDepth))
 		if line == 0 {
 			prefix.WriteString(RenderStarter(s, m.Symbols, maxDepth))
 			if lineCount > 1 {
@@ -514,11 +822,51 @@ func (m *Model) renderPrefixForMultiLineNode(t Node, lineCount int) string {
 	return prefix.String()
 }
 
+// renderPrefixForWrappedNode renders the prefix of a single line node which has been
+// wrapped over lineCount lines. The continuation lines keep the guides of the node's
+// ancestors and connect to the node's next sibling, if any.
+func (m *Model) renderPrefixForWrappedNode(t Node, lineCount int) string {
+	maxDepth := m.depth(t)
+
+	s := m.symbolStyle(t, maxDepth, maxDepth)
+
+	prefix := strings.Builder{}
+	for line := 0; line < lineCount; line++ {
+		prefix.WriteString(m.renderAncestorSymbols(t, maxDepth))
+		switch {
+		case line == 0:
+			prefix.WriteString(m.getTreeSymbolForPos(t, maxDepth, maxDepth))
+		case isLastNode(t):
+			prefix.WriteString(Padding(s, m.Symbols, maxDepth))
+		default:
+			prefix.WriteString(RenderConnector(s, m.Symbols, maxDepth))
+		}
+		if line < lineCount-1 {
+			prefix.WriteRune('\n')
+		}
+	}
+
+	return prefix.String()
+}
+
+// renderAncestorSymbols renders the tree symbols for the positions before the node's own depth.
+func (m *Model) renderAncestorSymbols(t Node, maxDepth int) string {
+	prefix := strings.Builder{}
+	for lvl := 0; lvl <= maxDepth-1; lvl++ {
+		prefix.WriteString(m.getTreeSymbolForPos(t, lvl, maxDepth))
+	}
+	return prefix.String()
+}
+
 func (m *Model) render() []string {
 	if m.Model.Height()+m.Model.Width() == 0 {
 		return nil
 	}
 
+	m.updateActivePath()
+	visible := m.tree.sequentialNodes()
+	m.updateIconWidth(visible)
+	m.updateColumnWidths(visible)
 	return m.renderNodes(m.Children())
 }
 
@@ -529,13 +877,30 @@ func (m *Model) renderNode(t Node) string {
 		return ""
 	}
 
+	node := m.renderRow(t)
+	if (m.expandAll || isExpanded(t)) && len(t.Children()) > 0 {
+		renderedChildren := m.renderNodes(t.Children())
+		node = lipgloss.JoinVertical(lipgloss.Top, node, lipgloss.JoinVertical(lipgloss.Left, renderedChildren...))
+	}
+
+	return node
+}
+
+// renderRow renders the node t, with its tree symbols, but without its children.
+func (m *Model) renderRow(t Node) string {
 	prefix := ""
 	name := ""
 	name = t.View().Content
 
 	style := m.Styles.Line
+	if isMarked(t) {
+		style = m.Styles.Marked
+	}
 	if isSelected(t) {
 		style = m.Styles.Selected
+		if !m.focus {
+			style = m.Styles.BlurredSelected
+		}
 	}
 
 	if lineCount := lipgloss.Height(name); lineCount > 1 {
@@ -544,19 +909,45 @@ func (m *Model) renderNode(t Node) string {
 		prefix = m.renderPrefixForSingleLineNode(t)
 	}
 
-	pw := lipgloss.Width(prefix)
-	nw := m.Width() - pw
+	if m.contentWidth() <= 0 {
+		// NOTE(marius): without a viewport width, like when using RenderAll, the content is not constrained
+		return m.joinRow(t, prefix, style.Render(name))
+	}
+
+	pw := lipgloss.Width(prefix) + m.columnsWidth()
+	nw := m.contentWidth() - pw
 	render := style.Width(nw).MaxWidth(nw - 1).Render
-	if lipgloss.Width(name) > nw {
+	switch {
+	case m.Overflow == OverflowScroll:
+		name = clipLines(name, nw-1, m.rowXOffset(t))
+	case m.Overflow == OverflowWrap && lipgloss.Height(name) == 1 && lipgloss.Width(name) > nw-1:
+		name = wrap(name, nw-1)
+		prefix = m.renderPrefixForWrappedNode(t, lipgloss.Height(name))
+	case lipgloss.Width(name) > nw:
 		name = truncate.StringWithTail(name, uint(nw-1), Ellipsis)
 	}
-	node := lipgloss.JoinHorizontal(lipgloss.Left, prefix, render(name))
-	if isExpanded(t) && len(t.Children()) > 0 {
-		renderedChildren := m.renderNodes(t.Children())
-		node = lipgloss.JoinVertical(lipgloss.Top, node, lipgloss.JoinVertical(lipgloss.Left, renderedChildren...))
+	return m.joinRow(t, prefix, render(name))
+}
+
+// joinRow puts together the tree symbols, the optional expander and icon columns and the content of node t.
+func (m *Model) joinRow(t Node, prefix, content string) string {
+	columns := []string{prefix}
+	if m.leadingWidth() > 0 {
+		columns = []string{m.renderColumns(t, lipgloss.Height(content)), prefix}
+	}
+	if m.ShowExpander {
+		columns = append(columns, m.renderExpander(t, lipgloss.Height(content)))
 	}
+	if m.iconWidth > 0 {
+		columns = append(columns, m.renderIcon(t, lipgloss.Height(content)))
+	}
+	columns = append(columns, content)
+	return lipgloss.JoinHorizontal(lipgloss.Left, columns...)
+}
 
-	return node
+// columnsWidth returns the width of the optional columns around the tree symbols.
+func (m *Model) columnsWidth() int {
+	return m.leadingWidth() + m.expanderWidth() + m.iconWidth
 }
 
 func (m *Model) renderNodes(nl Nodes) []string {
@@ -582,7 +973,7 @@ func (m *Model) renderNodes(nl Nodes) []string {
 			hints |= NodeLastChild
 		}
 
-		n.Update(n.State() | hints)
+		n.Update(n.State()&^(NodeLastChild|nodeHasPreviousSibling) | hints)
 		if out := m.renderNode(n); len(out) > 0 {
 			rendered = append(rendered, out)
 		}
@@ -615,6 +1006,15 @@ func isSelected(n Node) bool {
 	return n.State().Is(NodeSelected)
 }
 
+func isLoading(n Node) bool {
+	return n.State().Is(NodeLoading)
+}
+
+func isMarked(n Node) bool {
+	mn, ok := n.(Marker)
+	return ok && mn.Marked()
+}
+
 func hasPreviousSibling(n Node) bool {
 	return n.State().Is(nodeHasPreviousSibling)
 }
@@ -21,6 +21,7 @@ type n struct {
 	p *n
 	c []*n
 	s NodeState
+	m bool
 }
 
 func (n *n) Parent() Node {
@@ -56,6 +57,10 @@ func (n *n) State() NodeState {
 	return n.s
 }
 
+func (n *n) Marked() bool {
+	return n != nil && n.m
+}
+
 func (n *n) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if n == nil {
 		return n, nil
@@ -78,6 +83,12 @@ func st(st NodeState) func(*n) {
 	}
 }
 
+func mk() func(*n) {
+	return func(nn *n) {
+		nn.m = true
+	}
+}
+
 func c(c ...*n) func(*n) {
 	return func(nn *n) {
 		for i, nnn := range c {
@@ -121,26 +132,30 @@ func tn(name string, fns ...func(*n)) *n {
 // m.SetHeight(12)
 // m.render()
 
-var treeOne = tn("tmp",
-	st(NodeLastChild),
-	c(
-		tn("example1"),
-		tn("test",
-			c(
-				tn("example",
-					c(
-						tn("file2"),
-						tn("file4"),
-						tn("lastchild", st(NodeLastChild), c(tn("file", st(NodeLastChild)))),
+var treeOne = newTreeOne()
+
+func newTreeOne() *n {
+	return tn("tmp",
+		st(NodeLastChild),
+		c(
+			tn("example1"),
+			tn("test",
+				c(
+					tn("example",
+						c(
+							tn("file2"),
+							tn("file4"),
+							tn("lastchild", st(NodeLastChild), c(tn("file", st(NodeLastChild)))),
+						),
 					),
+					tn("file1"),
+					tn("file3"),
+					tn("file5", st(NodeLastChild)),
 				),
-				tn("file1"),
-				tn("file3"),
-				tn("file5", st(NodeLastChild)),
 			),
 		),
-	),
-)
+	)
+}
 
 func Test_showTreeSymbolAtPos(t *testing.T) {
 	type args struct {
@@ -994,6 +1009,7 @@ func TestModel_Blur(t *testing.T) {
 	if !m.Focused() {
 		t.Errorf("invalid Focused() value after initialization: %t, expected %t", m.Focused(), true)
 	}
+	m.cursor = 3
 	m.Blur()
 	if m.focus {
 		t.Errorf("invalid focus value after calling Blur(): %t, expected %t", m.focus, false)
@@ -1001,6 +1017,82 @@ func TestModel_Blur(t *testing.T) {
 	if m.Focused() {
 		t.Errorf("invalid Focused() value after calling Blur(): %t, expected %t", m.Focused(), false)
 	}
+	if m.cursor != 3 {
+		t.Errorf("invalid cursor after calling Blur(): %d, expected %d", m.cursor, 3)
+	}
+	m.Focus()
+	if m.cursor != 3 {
+		t.Errorf("invalid cursor after calling Focus(): %d, expected %d", m.cursor, 3)
+	}
+}
+
+func TestModel_Update_blurred(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        func(*n) tea.Msg
+		wantCursor int
+		wantWidth  int
+	}{
+		{
+			name:       "key press is ignored",
+			msg:        func(*n) tea.Msg { return tea.KeyPressMsg{Code: tea.KeyDown} },
+			wantCursor: 1,
+			wantWidth:  20,
+		},
+		{
+			name:       "click is ignored",
+			msg:        func(*n) tea.Msg { return tea.MouseClickMsg{Button: tea.MouseLeft, Y: 3} },
+			wantCursor: 1,
+			wantWidth:  20,
+		},
+		{
+			name:       "resize is applied",
+			msg:        func(*n) tea.Msg { return tea.WindowSizeMsg{Width: 30, Height: 10} },
+			wantCursor: 1,
+			wantWidth:  30,
+		},
+		{
+			name:       "goto node is applied",
+			msg:        func(tree *n) tea.Msg { return GotoNodeMsg{Node: tree.c[1]} },
+			wantCursor: 2,
+			wantWidth:  20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := newTreeOne()
+			m := mockModel(tree)
+			m.SetWidth(20)
+			m.SetHeight(10)
+			m.SetCursor(1)
+			m.Blur()
+
+			m.Update(tt.msg(tree))
+			if m.cursor != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", m.cursor, tt.wantCursor)
+			}
+			if m.Width() != tt.wantWidth {
+				t.Errorf("Width() = %d, want %d", m.Width(), tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestModel_renderRow_blurred(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.Styles.Selected = lipgloss.NewStyle().SetString("focused")
+	m.Styles.BlurredSelected = lipgloss.NewStyle().SetString("blurred")
+	m.SetCursor(1)
+	example1 := tree.c[0]
+
+	if got := m.renderRow(example1); !strings.Contains(got, "focused") {
+		t.Errorf("renderRow() focused = %q, want the Selected style", got)
+	}
+	m.Blur()
+	if got := m.renderRow(example1); !strings.Contains(got, "blurred") {
+		t.Errorf("renderRow() blurred = %q, want the BlurredSelected style", got)
+	}
 }
 
 func TestModel_View(t *testing.T) {
@@ -1413,3 +1505,137 @@ func TestModel_ToggleExpand(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_isActiveGuide(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.Styles.ActiveSymbol = Style(defaultStyle.Bold(true))
+	m.cursor = 7
+	m.updateActivePath()
+
+	var (
+		example1  = tree.c[0]
+		test      = tree.c[1]
+		example   = test.c[0]
+		file2     = example.c[0]
+		lastchild = example.c[2]
+		file      = lastchild.c[0]
+		file1     = test.c[1]
+	)
+	tests := []struct {
+		name string
+		node *n
+		want []bool
+	}{
+		{name: "tmp", node: tree, want: []bool{true}},
+		{name: "example1", node: example1, want: []bool{false, true}},
+		{name: "test", node: test, want: []bool{false, true}},
+		{name: "example", node: example, want: []bool{false, false, true}},
+		{name: "file2", node: file2, want: []bool{false, false, false, true}},
+		{name: "lastchild", node: lastchild, want: []bool{false, false, false, true}},
+		{name: "file", node: file, want: []bool{false, false, false, false, true}},
+		{name: "file1", node: file1, want: []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxDepth := getDepth(tt.node)
+			for pos, want := range tt.want {
+				if got := m.isActiveGuide(tt.node, pos, maxDepth); got != want {
+					t.Errorf("isActiveGuide() at pos %d = %t, want %t", pos, got, want)
+				}
+			}
+		})
+	}
+}
+
+type markerStyle struct {
+	w int
+}
+
+func (s markerStyle) Width(w int) DepthStyler {
+	s.w = w
+	return s
+}
+
+func (s markerStyle) Render(_ int, _ ...string) string {
+	return strings.Repeat("*", s.w)
+}
+
+func TestModel_renderPrefixForSingleLineNode_active(t *testing.T) {
+	tree := newTreeOne()
+	m := mockModel(tree)
+	m.Styles.ActiveSymbol = markerStyle{}
+	m.cursor = 7
+	m.updateActivePath()
+
+	file2 := tree.c[1].c[0].c[0]
+	want := "      │  ***"
+	if got := m.renderPrefixForSingleLineNode(file2); got != want {
+		t.Errorf("renderPrefixForSingleLineNode() = %q, want %q", got, want)
+	}
+}
+
+func TestModel_Refresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor int
+		want   string
+	}{
+		{name: "selected node moved", cursor: 2, want: "test"},
+		{name: "selected node removed", cursor: 1, want: "test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := newTreeOne()
+			m := mockModel(tree)
+			m.SetWidth(20)
+			m.SetHeight(10)
+			m.Init()
+			m.SetCursor(tt.cursor)
+
+			// remove example1, which is before test
+			removed := tree.c[0]
+			tree.c = tree.c[1:]
+			m.Update(ChangedMsg{Removed: Nodes{removed}})
+
+			if got := m.CurrentNode().(*n).n; got != tt.want {
+				t.Errorf("CurrentNode() = %q, want %q", got, tt.want)
+			}
+			if got := m.cursor; got != 1 {
+				t.Errorf("cursor = %d, want 1", got)
+			}
+			if isSelected(removed) {
+				t.Errorf("the removed node is still selected")
+			}
+		})
+	}
+}
+
+func TestModel_renderNodes_changed(t *testing.T) {
+	root := tn("root", st(NodeLastChild), c(tn("a"), tn("b")))
+	m := mockModel(root)
+	m.SetWidth(20)
+	m.SetHeight(5)
+	m.View()
+
+	root.c = append(root.c, tn("c", p(root)))
+	want := []string{
+		"└─ root",
+		"   ├─ a",
+		"   ├─ b",
+		"   └─ c",
+	}
+	if diff := cmp.Diff(want, viewLines(m)); diff != "" {
+		t.Errorf("View() after adding a node mismatch (-want +got):\n%s", diff)
+	}
+
+	root.c = root.c[1:]
+	want = []string{
+		"└─ root",
+		"   ├─ b",
+		"   └─ c",
+	}
+	if diff := cmp.Diff(want, viewLines(m)); diff != "" {
+		t.Errorf("View() after removing a node mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,78 @@
+package xmltree
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// parseHTML reads the HTML document from r with a tokenizer, building the elements as they are
+// written, without the implied ones, like <head> or <tbody>, which an HTML parser would add.
+func parseHTML(r io.Reader, cfg *config) (*Node, error) {
+	z := html.NewTokenizer(r)
+	doc := &Node{cfg: cfg, kind: Document}
+	cur := doc
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			doc.setState()
+			if err := z.Err(); err != io.EOF {
+				return doc, err
+			}
+			return doc, nil
+		}
+		t := z.Token()
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			n := &Node{cfg: cfg, parent: cur, kind: Element, name: t.Data, attrs: htmlAttrs(t.Attr)}
+			cur.children = append(cur.children, n)
+			if tt == html.StartTagToken && !isVoid(n.name) {
+				cur = n
+			}
+		case html.EndTagToken:
+			// The end tag closes its element and the unclosed elements inside it.
+			for n := cur; n.kind == Element; n = n.parent {
+				if strings.EqualFold(n.name, t.Data) {
+					cur = n.parent
+					break
+				}
+			}
+		case html.TextToken:
+			cur.children = append(cur.children, &Node{cfg: cfg, parent: cur, kind: Text, text: t.Data})
+		case html.CommentToken:
+			cur.children = append(cur.children, htmlComment(cfg, cur, t.Data))
+		case html.DoctypeToken:
+			cur.children = append(cur.children, &Node{cfg: cfg, parent: cur, kind: Directive, text: "DOCTYPE " + t.Data})
+		}
+	}
+}
+
+func htmlAttrs(attrs []html.Attribute) []xml.Attr {
+	result := make([]xml.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		result = append(result, xml.Attr{Name: xml.Name{Space: a.Namespace, Local: a.Key}, Value: a.Val})
+	}
+	return result
+}
+
+// htmlComment returns the node for a comment. The HTML tokenizer reads the processing
+// instructions, like the <?xml version="1.0"?> of the XHTML documents, as comments.
+func htmlComment(cfg *config, parent *Node, text string) *Node {
+	if inst, ok := strings.CutPrefix(text, "?"); ok && strings.HasSuffix(inst, "?") {
+		target, inst, _ := strings.Cut(strings.TrimSuffix(inst, "?"), " ")
+		return &Node{cfg: cfg, parent: parent, kind: ProcInst, name: target, text: inst}
+	}
+	return &Node{cfg: cfg, parent: parent, kind: Comment, text: text}
+}
+
+// isVoid checks if the HTML element can't have any content, and so it has no end tag.
+func isVoid(name string) bool {
+	for _, v := range xml.HTMLAutoClose {
+		if strings.EqualFold(v, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,382 @@
+// Package xmltree provides tree nodes for exploring XML and HTML documents.
+//
+// The elements are shown with their attributes, and their contents as children: the text,
+// the comments and the other elements. The text consisting only of white space, which is
+// usually the indentation of the document, is hidden until ShowWhitespace is called:
+//
+//	doc, err := xmltree.Parse(os.Stdin)
+//	if err != nil {
+//		return err
+//	}
+//	t := tree.New(tree.Nodes{doc})
+//
+// The HTML documents, which are not well-formed XML most of the time, can be read by
+// using the HTML option.
+package xmltree
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+// Kind is the kind of a Node.
+type Kind int
+
+const (
+	Document Kind = iota
+	Element
+	Text
+	Comment
+	// ProcInst is a processing instruction, like <?xml version="1.0"?>.
+	ProcInst
+	// Directive is a directive, like <!DOCTYPE html>.
+	Directive
+)
+
+// Styles contains the style definitions for the XML syntax.
+type Styles struct {
+	Tag       lipgloss.Style
+	AttrName  lipgloss.Style
+	AttrValue lipgloss.Style
+	Text      lipgloss.Style
+	// Whitespace is used for the text consisting only of white space.
+	Whitespace lipgloss.Style
+	Comment    lipgloss.Style
+}
+
+// DefaultStyles returns a set of default style definitions for the XML syntax.
+func DefaultStyles() Styles {
+	return Styles{
+		Tag:        lipgloss.NewStyle().Foreground(lipgloss.Blue),
+		AttrName:   lipgloss.NewStyle().Foreground(lipgloss.Cyan),
+		AttrValue:  lipgloss.NewStyle().Foreground(lipgloss.Green),
+		Text:       lipgloss.NewStyle(),
+		Whitespace: lipgloss.NewStyle().Faint(true),
+		Comment:    lipgloss.NewStyle().Faint(true).Italic(true),
+	}
+}
+
+type config struct {
+	styles     Styles
+	html       bool
+	whitespace bool
+}
+
+// Option configures how the nodes are built from the document.
+type Option func(*config)
+
+// WithStyles sets the Styles used for rendering the nodes.
+func WithStyles(s Styles) Option {
+	return func(c *config) {
+		c.styles = s
+	}
+}
+
+// HTML reads the document as HTML, with the tokenizer of golang.org/x/net/html: the void elements,
+// like <br>, don't need to be closed, the unclosed elements are closed by the end tags of their
+// ancestors, the stray end tags are ignored, the HTML entities and unquoted attribute values are
+// accepted, and the contents of <script> and <style> are read as raw text.
+func HTML() Option {
+	return func(c *config) {
+		c.html = true
+	}
+}
+
+// WithWhitespace shows the text consisting only of white space from the start.
+func WithWhitespace() Option {
+	return func(c *config) {
+		c.whitespace = true
+	}
+}
+
+// Node is a tree.Node for a document, or one of its elements, texts, comments
+// processing instructions or directives.
+type Node struct {
+	cfg *config
+
+	parent *Node
+	kind   Kind
+	// name is the name of the element, or the target of the processing instruction.
+	name  string
+	attrs []xml.Attr
+	// text is the text, or the contents of the comment, processing instruction or directive.
+	text     string
+	children []*Node
+	state    tree.NodeState
+}
+
+// Parse reads the document from r. The names of the elements and attributes are kept
+// as written, with their namespace prefix.
+//
+// In the HTML mode the syntax errors don't stop the parsing, only the errors of r do. The nodes
+// read before such an error are returned together with it.
+func Parse(r io.Reader, opts ...Option) (*Node, error) {
+	cfg := config{styles: DefaultStyles()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.html {
+		return parseHTML(r, &cfg)
+	}
+	dec := xml.NewDecoder(r)
+	doc := &Node{cfg: &cfg, kind: Document}
+	cur := doc
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &Node{cfg: &cfg, parent: cur, kind: Element, name: qualified(t.Name), attrs: t.Attr}
+			cur.children = append(cur.children, n)
+			cur = n
+		case xml.EndElement:
+			name := qualified(t.Name)
+			if cur.kind != Element || cur.name != name {
+				return nil, fmt.Errorf("line %d: unexpected end element </%s>", line(dec), name)
+			}
+			cur = cur.parent
+		case xml.CharData:
+			cur.children = append(cur.children, &Node{cfg: &cfg, parent: cur, kind: Text, text: string(t)})
+		case xml.Comment:
+			cur.children = append(cur.children, &Node{cfg: &cfg, parent: cur, kind: Comment, text: string(t)})
+		case xml.ProcInst:
+			cur.children = append(cur.children, &Node{cfg: &cfg, parent: cur, kind: ProcInst, name: t.Target, text: string(t.Inst)})
+		case xml.Directive:
+			cur.children = append(cur.children, &Node{cfg: &cfg, parent: cur, kind: Directive, text: string(t)})
+		}
+	}
+	if cur != doc {
+		return nil, fmt.Errorf("line %d: unclosed element <%s>", line(dec), cur.name)
+	}
+	doc.setState()
+	return doc, nil
+}
+
+func line(dec *xml.Decoder) int {
+	l, _ := dec.InputPos()
+	return l
+}
+
+func qualified(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// setState marks the elements with visible children as collapsible.
+func (n *Node) setState() {
+	n.state &^= tree.NodeCollapsible
+	if len(n.Children()) > 0 {
+		n.state |= tree.NodeCollapsible
+	}
+	for _, c := range n.children {
+		c.setState()
+	}
+}
+
+// ShowWhitespace toggles the visibility of the text consisting only of white space, in the whole
+// document of n. The returned command notifies the tree Model of the change.
+func (n *Node) ShowWhitespace(on bool) tea.Cmd {
+	if n.cfg.whitespace == on {
+		return nil
+	}
+	n.cfg.whitespace = on
+	root := n
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.setState()
+	return func() tea.Msg {
+		return tree.ChangedMsg{}
+	}
+}
+
+// IsWhitespace reports whether the node is a text consisting only of white space.
+func (n *Node) IsWhitespace() bool {
+	return n.kind == Text && strings.TrimSpace(n.text) == ""
+}
+
+// Kind returns the kind of the node.
+func (n *Node) Kind() Kind {
+	return n.kind
+}
+
+// Name returns the name of the element, with its namespace prefix, or the target of the processing instruction.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Attrs returns the attributes of the element.
+func (n *Node) Attrs() []xml.Attr {
+	return n.attrs
+}
+
+// Attr returns the value of the attribute of the element with the name, with its namespace prefix.
+func (n *Node) Attr(name string) (string, bool) {
+	for _, a := range n.attrs {
+		if qualified(a.Name) == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the text of the node, or the contents of the comment, processing instruction or directive.
+func (n *Node) Text() string {
+	return n.text
+}
+
+// XPath returns the location path of the node, like /soap:Envelope/soap:Body/item[2]/text().
+// The position of the node is only included when it has siblings of the same type and name.
+func (n *Node) XPath() string {
+	if n.parent == nil {
+		return "/"
+	}
+	step := ""
+	switch n.kind {
+	case Element:
+		step = n.name
+	case Text:
+		step = "text()"
+	case Comment:
+		step = "comment()"
+	case ProcInst:
+		step = "processing-instruction(" + strconv.Quote(n.name) + ")"
+	default:
+		return n.parent.XPath()
+	}
+	position, count := 0, 0
+	for _, s := range n.parent.children {
+		if s.kind == n.kind && s.name == n.name {
+			count++
+		}
+		if s == n {
+			position = count
+		}
+	}
+	if count > 1 {
+		step += "[" + strconv.Itoa(position) + "]"
+	}
+	if n.parent.parent == nil {
+		return "/" + step
+	}
+	return n.parent.XPath() + "/" + step
+}
+
+// CopyXPath returns a command which copies the XPath of node n to the system clipboard.
+// It does nothing for nodes which are not from this package.
+func CopyXPath(n tree.Node) tea.Cmd {
+	xn, ok := n.(*Node)
+	if !ok || xn == nil {
+		return nil
+	}
+	return tea.SetClipboard(xn.XPath())
+}
+
+// Title returns the name of the element, or the kind of the other nodes, to be used by the tree.Breadcrumb.
+func (n *Node) Title() string {
+	switch n.kind {
+	case Element:
+		return n.name
+	case Text:
+		return "text()"
+	case Comment:
+		return "comment()"
+	}
+	return "/"
+}
+
+func (n *Node) startTag() string {
+	s := n.cfg.styles
+	b := strings.Builder{}
+	b.WriteString(s.Tag.Render("<" + n.name))
+	for _, a := range n.attrs {
+		b.WriteString(" " + s.AttrName.Render(qualified(a.Name)) + s.Tag.Render("="))
+		b.WriteString(s.AttrValue.Render(strconv.Quote(a.Value)))
+	}
+	return b.String()
+}
+
+func (n *Node) View() tea.View {
+	s := n.cfg.styles
+	var v string
+	switch n.kind {
+	case Document:
+		v = s.Tag.Render("/")
+	case Element:
+		switch {
+		case len(n.Children()) == 0:
+			v = n.startTag() + s.Tag.Render("/>")
+		case n.state.Is(tree.NodeCollapsed):
+			v = n.startTag() + s.Tag.Render(">") + tree.Ellipsis + s.Tag.Render("</"+n.name+">")
+		default:
+			v = n.startTag() + s.Tag.Render(">")
+		}
+	case Text:
+		if n.IsWhitespace() {
+			r := strings.NewReplacer("\n", "↵", "\r", "", "\t", "→", " ", "·")
+			v = s.Whitespace.Render(r.Replace(n.text))
+		} else {
+			v = s.Text.Render(strings.Join(strings.Fields(n.text), " "))
+		}
+	case Comment:
+		v = s.Comment.Render("<!-- " + strings.Join(strings.Fields(n.text), " ") + " -->")
+	case ProcInst:
+		v = s.Comment.Render("<?" + n.name + " " + strings.TrimSpace(n.text) + "?>")
+	case Directive:
+		v = s.Comment.Render("<!" + n.text + ">")
+	}
+	return tea.NewView(v)
+}
+
+func (n *Node) Parent() tree.Node {
+	if n == nil || n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *Node) Init() tea.Cmd {
+	return nil
+}
+
+// Children returns the contents of the element, without the text consisting only
+// of white space, unless it was made visible with ShowWhitespace.
+func (n *Node) Children() tree.Nodes {
+	nodes := make(tree.Nodes, 0, len(n.children))
+	for _, c := range n.children {
+		if !n.cfg.whitespace && c.IsWhitespace() {
+			continue
+		}
+		nodes = append(nodes, c)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes
+}
+
+func (n *Node) State() tree.NodeState {
+	return n.state
+}
+
+func (n *Node) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m := msg.(type) {
+	case tree.NodeState:
+		n.state = m
+	}
+	return n, nil
+}
@@ -0,0 +1,246 @@
+package xmltree
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+	tree "github.com/mariusor/bubbles-tree"
+)
+
+const soap = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">
+  <soap:Body>
+    <!-- the response -->
+    <m:GetPriceResponse xmlns:m="https://www.example.org/stock">
+      <m:Price currency="EUR">34.5</m:Price>
+      <m:Price currency="USD">37.1</m:Price>
+      <m:Empty/>
+    </m:GetPriceResponse>
+  </soap:Body>
+</soap:Envelope>
+`
+
+// plain renders the nodes without colors.
+var plain = WithStyles(Styles{})
+
+// views returns the views of the nodes, with the children of the expanded ones indented.
+func views(nodes tree.Nodes) []string {
+	result := make([]string, 0)
+	for _, n := range nodes {
+		result = append(result, n.View().Content)
+		if !n.State().Is(tree.NodeCollapsed) {
+			for _, c := range views(n.Children()) {
+				result = append(result, "  "+c)
+			}
+		}
+	}
+	return result
+}
+
+// find returns the first node in the tree of n for which match returns true.
+func find(n *Node, match func(*Node) bool) *Node {
+	if match(n) {
+		return n
+	}
+	for _, c := range n.children {
+		if f := find(c, match); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		opts    []Option
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "soap",
+			src:  soap,
+			want: []string{
+				`<?xml version="1.0" encoding="UTF-8"?>`,
+				`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">`,
+				`  <soap:Body>`,
+				`    <!-- the response -->`,
+				`    <m:GetPriceResponse xmlns:m="https://www.example.org/stock">`,
+				`      <m:Price currency="EUR">`,
+				`        34.5`,
+				`      <m:Price currency="USD">`,
+				`        37.1`,
+				`      <m:Empty/>`,
+			},
+		},
+		{
+			name: "whitespace",
+			src:  "<a>\n\t<b> x </b>\n</a>",
+			opts: []Option{WithWhitespace()},
+			want: []string{"<a>", "  ↵→", "  <b>", "    x", "  ↵"},
+		},
+		{
+			name: "html",
+			src: `<!DOCTYPE html><html><body class=main><p>One<br>two<p>Three &amp; &copy;</div>` +
+				`<input disabled></body></html>`,
+			opts: []Option{HTML()},
+			want: []string{
+				"<!DOCTYPE html>",
+				"<html>",
+				`  <body class="main">`,
+				"    <p>",
+				"      One",
+				"      <br/>",
+				"      two",
+				"      <p>",
+				"        Three & ©",
+				`        <input disabled=""/>`,
+			},
+		},
+		{
+			name: "html raw text",
+			src: `<head><style>/* a<b */ p > a { color: red }</style></head>` +
+				`<body><script>for (i = 0; i<n; i++) {}</script><img src=foo.png alt=a/b><p>after</p></body>`,
+			opts: []Option{HTML()},
+			want: []string{
+				"<head>",
+				"  <style>",
+				"    /* a<b */ p > a { color: red }",
+				"<body>",
+				"  <script>",
+				"    for (i = 0; i<n; i++) {}",
+				`  <img src="foo.png" alt="a/b"/>`,
+				"  <p>",
+				"    after",
+			},
+		},
+		{
+			name: "xhtml",
+			src:  `<?xml version="1.0"?><html><!-- x --></html>`,
+			opts: []Option{HTML()},
+			want: []string{`<?xml version="1.0"?>`, "<html>", "  <!-- x -->"},
+		},
+		{
+			name:    "mismatched end tag",
+			src:     "<a><b></a>",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed element",
+			src:     "<a><b></b>",
+			wantErr: true,
+		},
+		{
+			name:    "html entity in xml",
+			src:     "<a>&copy;</a>",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Parse(strings.NewReader(tt.src), append([]Option{plain}, tt.opts...)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, views(n.Children())); diff != "" {
+				t.Errorf("Parse() views mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_htmlError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("<ul><li>one<li>two</ul>"), iotest.ErrReader(errors.New("broken pipe")))
+	n, err := Parse(r, HTML(), plain)
+	if err == nil {
+		t.Fatalf("Parse() error = nil")
+	}
+	if n == nil {
+		t.Fatalf("Parse() returned no nodes with the error in HTML mode")
+	}
+	want := []string{"<ul>", "  <li>", "    one", "    <li>", "      two"}
+	if diff := cmp.Diff(want, views(n.Children())); diff != "" {
+		t.Errorf("Parse() views mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNode_XPath(t *testing.T) {
+	doc, err := Parse(strings.NewReader(soap))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	usd := find(doc, func(n *Node) bool {
+		v, _ := n.Attr("currency")
+		return v == "USD"
+	})
+	tests := []struct {
+		name string
+		node *Node
+		want string
+	}{
+		{name: "document", node: doc, want: "/"},
+		{name: "root", node: find(doc, func(n *Node) bool { return n.Name() == "soap:Envelope" }), want: "/soap:Envelope"},
+		{name: "position", node: usd, want: "/soap:Envelope/soap:Body/m:GetPriceResponse/m:Price[2]"},
+		{name: "text", node: usd.children[0], want: "/soap:Envelope/soap:Body/m:GetPriceResponse/m:Price[2]/text()"},
+		{name: "comment", node: find(doc, func(n *Node) bool { return n.Kind() == Comment }), want: "/soap:Envelope/soap:Body/comment()"},
+		{name: "processing instruction", node: doc.children[0], want: `/processing-instruction("xml")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.XPath(); got != tt.want {
+				t.Errorf("XPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if CopyXPath(usd) == nil {
+		t.Errorf("CopyXPath() = nil, want a command")
+	}
+	if CopyXPath(nil) != nil {
+		t.Errorf("CopyXPath(nil) != nil")
+	}
+}
+
+func TestNode_ShowWhitespace(t *testing.T) {
+	doc, err := Parse(strings.NewReader("<a>\n  <b/>\n</a>"), plain)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	a := doc.children[0]
+	if got := len(a.Children()); got != 1 {
+		t.Errorf("len(Children()) = %d, want 1", got)
+	}
+	cmd := a.ShowWhitespace(true)
+	if cmd == nil {
+		t.Fatalf("ShowWhitespace() returned no command")
+	}
+	if _, ok := cmd().(tree.ChangedMsg); !ok {
+		t.Errorf("ShowWhitespace() command returned %T", cmd())
+	}
+	if got := len(a.Children()); got != 3 {
+		t.Errorf("len(Children()) = %d, want 3", got)
+	}
+	if a.ShowWhitespace(true) != nil {
+		t.Errorf("ShowWhitespace() without a change returned a command")
+	}
+
+	ws, err := Parse(strings.NewReader("<a> </a>"), plain)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	a = ws.children[0]
+	if a.State().Is(tree.NodeCollapsible) || a.View().Content != "<a/>" {
+		t.Errorf("an element with only white space should look empty, got %q", a.View().Content)
+	}
+	a.ShowWhitespace(true)
+	if !a.State().Is(tree.NodeCollapsible) || a.View().Content != "<a>" {
+		t.Errorf("the white space should be visible, got %q", a.View().Content)
+	}
+}